@@ -0,0 +1,85 @@
+package main
+
+import (
+	"app/internal/blockchain"
+	"app/internal/database"
+	"app/internal/importer"
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+)
+
+// Backfills the FIL and iFIL transfers of an address from the chain. Without -from
+// the import resumes after the last scanned block of the address.
+func main() {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	address := flag.String("address", "", "address to import transfers of")
+	fromBlock := flag.String("from", "", "first block to scan, defaults to the block after the checkpoint")
+	toBlock := flag.String("to", "", "last block to scan, defaults to the chain head")
+	flag.Parse()
+
+	if !common.IsHexAddress(*address) {
+		logger.Fatal("Invalid -address", zap.String("address", *address))
+	}
+	req := importer.Request{Address: common.HexToAddress(*address)}
+	req.FromBlock = parseBlock(logger, "-from", *fromBlock)
+	req.ToBlock = parseBlock(logger, "-to", *toBlock)
+
+	chainId, err := blockchain.StringToChainId(os.Getenv("CHAIN_ID"))
+	if err != nil {
+		logger.Fatal(err.Error())
+	}
+
+	var ifil *common.Address
+	if v := os.Getenv("IFIL_ADDRESS"); v != "" {
+		if !common.IsHexAddress(v) {
+			logger.Fatal("Invalid IFIL_ADDRESS", zap.String("value", v))
+		}
+		token := common.HexToAddress(v)
+		ifil = &token
+	}
+
+	client, err := blockchain.NewClient(logger, chainId, blockchain.DefaultTestnetRPCConfig, nil)
+	if err != nil {
+		logger.Fatal("Failed to initialize blockchain client", zap.Error(err))
+	}
+
+	dbDSN := os.Getenv("DATABASE_DSN")
+	if dbDSN == "" {
+		logger.Fatal("Missing DATABASE_DSN")
+	}
+
+	dbDriver, err := database.NewDriver(logger, dbDSN)
+	if err != nil {
+		logger.Fatal("Failed to connect to database", zap.Error(err))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// progress is checkpointed, an interrupted import continues where it stopped
+	result, err := importer.New(logger, client, dbDriver, ifil).Import(ctx, req)
+	if err != nil {
+		logger.Fatal("Import failed", zap.Error(err))
+	}
+	logger.Info("Import finished", zap.String("address", *address), zap.Uint64("from_block", result.FromBlock), zap.Uint64("to_block", result.ToBlock), zap.Int64("imported", result.Imported))
+}
+
+func parseBlock(logger *zap.Logger, name, value string) *uint64 {
+	if value == "" {
+		return nil
+	}
+	block, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		logger.Fatal("Invalid "+name, zap.String("value", value))
+	}
+	return &block
+}
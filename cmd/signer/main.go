@@ -0,0 +1,84 @@
+package main
+
+import (
+	"app/internal/wallet"
+	"context"
+	"flag"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+)
+
+// Stand-in for Clef exposing the account_* JSON-RPC API over HTTP or a Unix socket.
+// Do not use it in production: every request within -max-value is approved automatically.
+func main() {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	keystoreDir := flag.String("keystore", "keystore", "keystore directory")
+	httpAddr := flag.String("http", "", "HTTP listen address, e.g. :8550")
+	ipcPath := flag.String("ipc", "", "Unix socket path, e.g. /tmp/signer.ipc")
+	maxValue := flag.String("max-value", "", "reject transfers above this amount of attoFIL")
+	flag.Parse()
+
+	passphrase := os.Getenv("SIGNER_PASSPHRASE")
+	if passphrase == "" {
+		logger.Fatal("Missing SIGNER_PASSPHRASE")
+	}
+
+	if (*httpAddr == "") == (*ipcPath == "") {
+		logger.Fatal("Exactly one of -http or -ipc must be set")
+	}
+
+	var limit *big.Int
+	if *maxValue != "" {
+		var ok bool
+		limit, ok = new(big.Int).SetString(*maxValue, 10)
+		if !ok {
+			logger.Fatal("Invalid -max-value", zap.String("max_value", *maxValue))
+		}
+	}
+
+	w, err := wallet.NewKeystore(logger, *keystoreDir, passphrase)
+	if err != nil {
+		logger.Fatal("Failed to open keystore", zap.Error(err))
+	}
+
+	srv := rpc.NewServer()
+	defer srv.Stop()
+	if err := srv.RegisterName("account", wallet.NewSignerService(logger, w, limit)); err != nil {
+		logger.Fatal("Failed to register signer service", zap.Error(err))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *httpAddr != "" {
+		httpSrv := &http.Server{Addr: *httpAddr, Handler: srv}
+		go func() {
+			logger.Info("Signer listening", zap.String("http", *httpAddr))
+			if err := httpSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Fatal("Signer HTTP server failed", zap.Error(err))
+			}
+		}()
+		defer httpSrv.Shutdown(context.Background())
+	} else {
+		listener, err := net.Listen("unix", *ipcPath)
+		if err != nil {
+			logger.Fatal("Failed to listen on unix socket", zap.Error(err))
+		}
+		defer listener.Close()
+
+		logger.Info("Signer listening", zap.String("ipc", *ipcPath))
+		go srv.ServeListener(listener)
+	}
+
+	<-ctx.Done()
+	logger.Info("Shutting down signer")
+}
@@ -2,13 +2,12 @@ package blockchain
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"fmt"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind/v2"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/glifio/go-pools/sdk"
 	glifio "github.com/glifio/go-pools/types"
 	"github.com/pkg/errors"
@@ -31,25 +30,59 @@ func StringToChainId(s string) (ChainId, error) {
 	return 0, errors.New("unknown chain")
 }
 
+var (
+	ErrTxNotFound      = errors.New("transaction not found")
+	ErrReceiptNotFound = errors.New("transaction receipt not found")
+)
+
 type Client interface {
 	GetBalances(ctx context.Context, address common.Address) (*WalletBalance, error)
-	SubmitIFILTransaction(ctx context.Context, signer *ecdsa.PrivateKey, receiver common.Address, amount *big.Int) (*types.Transaction, error)
-	SubmitFILTransaction(ctx context.Context, signer *ecdsa.PrivateKey, receiver common.Address, amount *big.Int) (*types.Transaction, error)
+	SignIFILTransaction(ctx context.Context, signer Signer, receiver common.Address, amount *big.Int, fees FeeOptions) (*types.Transaction, error)
+	SignFILTransaction(ctx context.Context, signer Signer, receiver common.Address, amount *big.Int, fees FeeOptions) (*types.Transaction, error)
+	SignTokenTransaction(ctx context.Context, signer Signer, token, receiver common.Address, amount *big.Int, fees FeeOptions) (*types.Transaction, error)
+	TokenBalance(ctx context.Context, token, account common.Address) (*big.Int, error)
+	TokenDecimals(ctx context.Context, token common.Address) (uint8, error)
+	SignPoolDeposit(ctx context.Context, signer Signer, amount *big.Int, fees FeeOptions) (*types.Transaction, error)
+	PreviewDeposit(ctx context.Context, amount *big.Int) (*big.Int, error)
+	PreviewPoolExit(ctx context.Context, exit PoolExit) (*big.Int, *big.Int, error)
+	PoolAllowance(ctx context.Context, owner common.Address) (*big.Int, error)
+	SignPoolApproval(ctx context.Context, signer Signer, amount *big.Int, fees FeeOptions) (*types.Transaction, error)
+	SignPoolExit(ctx context.Context, signer Signer, exit PoolExit, fees FeeOptions) (*types.Transaction, error)
+	ReleaseNonce(sender common.Address, nonce uint64)
+	ResetNonces(sender common.Address)
+	PrepareFILTransaction(ctx context.Context, sender, receiver common.Address, amount *big.Int, fees FeeOptions) (*types.Transaction, error)
+	SuggestFees(ctx context.Context, sender, receiver common.Address, amount *big.Int) (*FeeSuggestions, error)
+	SpeedUpTransaction(ctx context.Context, signer Signer, hash common.Hash, fees FeeOptions) (*types.Transaction, error)
+	CancelTransaction(ctx context.Context, signer Signer, hash common.Hash, fees FeeOptions) (*types.Transaction, error)
+	GetTransaction(ctx context.Context, hash common.Hash) (*TransactionInfo, error)
+	GetTransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	NonceAt(ctx context.Context, account common.Address) (uint64, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+	FindFILTransfers(ctx context.Context, filter TransferFilter, from, to uint64) ([]Transfer, error)
+	FindTokenTransfers(ctx context.Context, token common.Address, filter TransferFilter, from, to uint64) ([]Transfer, error)
+	ChainID() *big.Int
 }
 
 type client struct {
-	logger *zap.Logger
-	sdk    glifio.PoolsSDK
+	logger  *zap.Logger
+	sdk     glifio.PoolsSDK
+	chainId ChainId
+	nonces  *NonceManager
 }
 
-func NewClient(logger *zap.Logger, id ChainId, connectInfo glifio.Extern) (Client, error) {
+// NewClient creates a client. nonces may be nil if the client does not sign
+// transactions which are queued before they are broadcast.
+func NewClient(logger *zap.Logger, id ChainId, connectInfo glifio.Extern, nonces NonceStore) (Client, error) {
 	initedSdk, err := sdk.New(context.Background(), big.NewInt(int64(id)), connectInfo)
 	if err != nil {
 		return nil, err
 	}
 	return &client{
-		logger: logger,
-		sdk:    initedSdk,
+		logger:  logger,
+		sdk:     initedSdk,
+		chainId: id,
+		nonces:  NewNonceManager(nonces),
 	}, nil
 }
 
@@ -76,6 +109,10 @@ func (wb *WalletBalance) GetIFIL() *big.Float {
 	return wb.ifil
 }
 
+func (c *client) ChainID() *big.Int {
+	return big.NewInt(int64(c.chainId))
+}
+
 func (c *client) GetBalances(ctx context.Context, address common.Address) (*WalletBalance, error) {
 	ethClient, err := c.sdk.Extern().ConnectEthClient()
 	if err != nil {
@@ -104,7 +141,9 @@ func (c *client) GetBalances(ctx context.Context, address common.Address) (*Wall
 	}, nil
 }
 
-func (c *client) SubmitIFILTransaction(ctx context.Context, signer *ecdsa.PrivateKey, receiver common.Address, amount *big.Int) (*types.Transaction, error) {
+// SignIFILTransaction signs an iFIL transfer with the next nonce of the signer
+// without broadcasting it. The nonce stays reserved until ReleaseNonce is called.
+func (c *client) SignIFILTransaction(ctx context.Context, signer Signer, receiver common.Address, amount *big.Int, fees FeeOptions) (*types.Transaction, error) {
 	ethClient, err := c.sdk.Extern().ConnectEthClient()
 	if err != nil {
 		return nil, err
@@ -116,87 +155,256 @@ func (c *client) SubmitIFILTransaction(ctx context.Context, signer *ecdsa.Privat
 		return nil, err
 	}
 
-	sender := crypto.PubkeyToAddress(signer.PublicKey)
+	gasFeeCap, gasTipCap, err := c.resolveFees(ctx, ethClient, fees)
+	if err != nil {
+		return nil, err
+	}
+
+	sender := signer.Address()
 	acts := c.sdk.Act()
 
-	signerFn := func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
-		if address != sender {
-			return nil, fmt.Errorf("signer address mismatch: expected %s, got %s", sender.Hex(), address.Hex())
-		}
-		signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), signer)
+	tx, err := c.signWithNonce(ctx, ethClient, sender, func(nonce uint64) (*types.Transaction, error) {
+		auth := transactOpts(ctx, signer, chainID, nonce, gasFeeCap, gasTipCap)
+		return acts.IFILTransfer(ctx, auth, receiver, amount)
+	})
+	if err != nil {
+		c.logger.Error("failed to sign transaction", zap.Error(err), zap.String("sender_address", sender.Hex()), zap.String("receiver_address", receiver.Hex()), zap.String("amount", amount.String()))
+		return nil, err
+	}
+	return tx, nil
+}
+
+// SignFILTransaction signs a FIL transfer with the next nonce of the signer
+// without broadcasting it. The nonce stays reserved until ReleaseNonce is called.
+func (c *client) SignFILTransaction(ctx context.Context, signer Signer, receiver common.Address, amount *big.Int, fees FeeOptions) (*types.Transaction, error) {
+	ethClient, err := c.sdk.Extern().ConnectEthClient()
+	if err != nil {
+		return nil, err
+	}
+	defer ethClient.Close()
+
+	chainID, err := ethClient.NetworkID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sender := signer.Address()
+	return c.signWithNonce(ctx, ethClient, sender, func(nonce uint64) (*types.Transaction, error) {
+		tx, err := c.buildFILTransaction(ctx, ethClient, chainID, nonce, sender, receiver, amount, fees)
 		if err != nil {
-			return nil, fmt.Errorf("failed to sign transaction: %w", err)
+			return nil, err
 		}
-		return signedTx, nil
+		return signer.SignTx(ctx, tx, chainID)
+	})
+}
+
+// transactOpts lets the SDK sign a contract call with signer and nonce without sending it.
+func transactOpts(ctx context.Context, signer Signer, chainID *big.Int, nonce uint64, gasFeeCap, gasTipCap *big.Int) *bind.TransactOpts {
+	sender := signer.Address()
+	return &bind.TransactOpts{
+		From:  sender,
+		Nonce: new(big.Int).SetUint64(nonce),
+		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if address != sender {
+				return nil, fmt.Errorf("signer address mismatch: expected %s, got %s", sender.Hex(), address.Hex())
+			}
+			signedTx, err := signer.SignTx(ctx, tx, chainID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to sign transaction: %w", err)
+			}
+			return signedTx, nil
+		},
+		GasFeeCap: gasFeeCap,
+		GasTipCap: gasTipCap,
+		Context:   ctx,
+		NoSend:    true,
 	}
+}
 
-	auth := &bind.TransactOpts{
-		From:    sender,
-		Signer:  signerFn,
-		Context: ctx,
+// signWithNonce reserves a nonce of sender and passes it to sign. The nonce is
+// released again if signing fails.
+func (c *client) signWithNonce(ctx context.Context, source NonceSource, sender common.Address, sign func(nonce uint64) (*types.Transaction, error)) (*types.Transaction, error) {
+	nonce, err := c.nonces.Next(ctx, source, sender)
+	if err != nil {
+		return nil, err
 	}
 
-	tx, err := acts.IFILTransfer(ctx, auth, receiver, amount)
+	tx, err := sign(nonce)
 	if err != nil {
-		c.logger.Error("failed to submit transaction", zap.Error(err), zap.String("sender_address", sender.Hex()), zap.String("receiver_address", receiver.Hex()), zap.String("amount", amount.String()))
+		c.nonces.Release(sender, nonce)
 		return nil, err
 	}
 	return tx, nil
 }
 
-func (c *client) SubmitFILTransaction(ctx context.Context, signer *ecdsa.PrivateKey, receiver common.Address, amount *big.Int) (*types.Transaction, error) {
+// ReleaseNonce returns the nonce of a signed transaction which will never be broadcast.
+func (c *client) ReleaseNonce(sender common.Address, nonce uint64) {
+	c.nonces.Release(sender, nonce)
+}
+
+// ResetNonces drops the nonces reserved for sender, the next one is taken from the
+// chain and the queued transactions again.
+func (c *client) ResetNonces(sender common.Address) {
+	c.nonces.Reset(sender)
+}
+
+// TransactionInfo is the on-chain view of a transaction. Receipt is nil while
+// the transaction is pending.
+type TransactionInfo struct {
+	Tx            *types.Transaction
+	From          common.Address
+	Pending       bool
+	Receipt       *types.Receipt
+	Confirmations uint64
+}
+
+func (c *client) GetTransaction(ctx context.Context, hash common.Hash) (*TransactionInfo, error) {
 	ethClient, err := c.sdk.Extern().ConnectEthClient()
 	if err != nil {
 		return nil, err
 	}
 	defer ethClient.Close()
 
-	chainID, err := ethClient.NetworkID(ctx)
+	tx, isPending, err := ethClient.TransactionByHash(ctx, hash)
+	if err != nil {
+		if errors.Is(err, ethereum.NotFound) {
+			return nil, ErrTxNotFound
+		}
+		return nil, fmt.Errorf("failed to get tx: %w", err)
+	}
+
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover tx sender: %w", err)
+	}
+
+	info := &TransactionInfo{Tx: tx, From: from, Pending: isPending}
+	if isPending {
+		return info, nil
+	}
+
+	receipt, err := ethClient.TransactionReceipt(ctx, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tx receipt: %w", err)
+	}
+	info.Receipt = receipt
+
+	head, err := ethClient.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block number: %w", err)
+	}
+	if block := receipt.BlockNumber.Uint64(); head >= block {
+		info.Confirmations = head - block + 1
+	}
+	return info, nil
+}
+
+func (c *client) GetTransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	ethClient, err := c.sdk.Extern().ConnectEthClient()
 	if err != nil {
 		return nil, err
 	}
+	defer ethClient.Close()
 
-	sender := crypto.PubkeyToAddress(signer.PublicKey)
+	receipt, err := ethClient.TransactionReceipt(ctx, hash)
+	if err != nil {
+		if errors.Is(err, ethereum.NotFound) {
+			return nil, ErrReceiptNotFound
+		}
+		return nil, fmt.Errorf("failed to get tx receipt: %w", err)
+	}
+	return receipt, nil
+}
 
-	nonce, err := ethClient.PendingNonceAt(ctx, sender)
+func (c *client) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	ethClient, err := c.sdk.Extern().ConnectEthClient()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get nonce: %w", err)
+		return err
 	}
+	defer ethClient.Close()
 
-	gasTipCap, err := ethClient.SuggestGasTipCap(ctx)
+	if err := ethClient.SendTransaction(ctx, tx); err != nil {
+		c.logger.Error("failed to send transaction", zap.Error(err), zap.String("hash", tx.Hash().Hex()))
+		return fmt.Errorf("failed to send tx: %w", err)
+	}
+	return nil
+}
+
+// NonceAt returns the nonce of the next transaction of account to be mined,
+// nonces below it are used up.
+func (c *client) NonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	ethClient, err := c.sdk.Extern().ConnectEthClient()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get gas tip cap: %w", err)
+		return 0, err
 	}
+	defer ethClient.Close()
 
-	gasFeeCap := new(big.Int).Add(gasTipCap, big.NewInt(2e9))
-	gasLimit, err := ethClient.EstimateGas(ctx, ethereum.CallMsg{
-		From:  sender,
-		To:    &receiver,
-		Value: amount,
-	})
+	nonce, err := ethClient.NonceAt(ctx, account, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to estimate gas: %w", err)
+		return 0, fmt.Errorf("failed to get nonce: %w", err)
 	}
+	return nonce, nil
+}
 
-	tx := types.NewTx(&types.DynamicFeeTx{
-		ChainID:   chainID,
-		Nonce:     nonce,
-		GasFeeCap: gasFeeCap,
-		GasTipCap: gasTipCap,
-		Gas:       uint64(float64(gasLimit) * 1.5), // to prevent 'out of gas' error
-		To:        &receiver,
-		Value:     amount,
-	})
+func (c *client) BlockNumber(ctx context.Context) (uint64, error) {
+	ethClient, err := c.sdk.Extern().ConnectEthClient()
+	if err != nil {
+		return 0, err
+	}
+	defer ethClient.Close()
+
+	head, err := ethClient.BlockNumber(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get block number: %w", err)
+	}
+	return head, nil
+}
 
-	signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), signer)
+func (c *client) PrepareFILTransaction(ctx context.Context, sender, receiver common.Address, amount *big.Int, fees FeeOptions) (*types.Transaction, error) {
+	ethClient, err := c.sdk.Extern().ConnectEthClient()
 	if err != nil {
-		return nil, fmt.Errorf("failed to sign tx: %w", err)
+		return nil, err
 	}
+	defer ethClient.Close()
 
-	err = ethClient.SendTransaction(ctx, signedTx)
+	chainID, err := ethClient.NetworkID(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send tx: %w", err)
+		return nil, err
 	}
 
-	return signedTx, nil
+	nonce, err := ethClient.PendingNonceAt(ctx, sender)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+
+	return c.buildFILTransaction(ctx, ethClient, chainID, nonce, sender, receiver, amount, fees)
+}
+
+// buildFILTransaction returns an unsigned EIP-1559 transfer with fees and gas limit filled in.
+func (c *client) buildFILTransaction(ctx context.Context, ethClient *ethclient.Client, chainID *big.Int, nonce uint64, sender, receiver common.Address, amount *big.Int, fees FeeOptions) (*types.Transaction, error) {
+	return c.buildTransaction(ctx, ethClient, chainID, nonce, sender, receiver, amount, nil, fees)
+}
+
+// buildTransaction returns an unsigned EIP-1559 transaction with fees and gas limit filled in.
+func (c *client) buildTransaction(ctx context.Context, ethClient *ethclient.Client, chainID *big.Int, nonce uint64, sender, to common.Address, value *big.Int, data []byte, fees FeeOptions) (*types.Transaction, error) {
+	gasFeeCap, gasTipCap, err := c.resolveFees(ctx, ethClient, fees)
+	if err != nil {
+		return nil, err
+	}
+
+	gasLimit, err := c.estimateGas(ctx, ethClient, sender, to, value, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		GasFeeCap: gasFeeCap,
+		GasTipCap: gasTipCap,
+		Gas:       gasLimit,
+		To:        &to,
+		Value:     value,
+		Data:      data,
+	}), nil
 }
@@ -13,7 +13,7 @@ func TestWallet(t *testing.T) {
 	const addr = "0xa986b79597588E4519FE0ABEfCBa37A343c44046"
 	logger := zap.NewNop()
 
-	cl, err := NewClient(logger, testNet, DefaultTestnetRPCConfig)
+	cl, err := NewClient(logger, testNet, DefaultTestnetRPCConfig, nil)
 	assert.NoError(t, err)
 
 	balances, err := cl.GetBalances(context.Background(), common.HexToAddress(addr))
@@ -0,0 +1,196 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/pkg/errors"
+	"math/big"
+)
+
+type FeeTier string
+
+const (
+	FeeTierSlow     FeeTier = "slow"
+	FeeTierStandard FeeTier = "standard"
+	FeeTierFast     FeeTier = "fast"
+)
+
+var FeeTiers = []FeeTier{FeeTierSlow, FeeTierStandard, FeeTierFast}
+
+var (
+	ErrUnknownFeeTier = errors.New("unknown fee tier")
+	ErrInvalidFeeCaps = errors.New("gas tip cap must not exceed gas fee cap")
+)
+
+// tip and base fee multipliers in percent per tier, fee cap = base fee * baseFeePct + tip
+var tierMultipliers = map[FeeTier]struct{ tipPct, baseFeePct int64 }{
+	FeeTierSlow:     {tipPct: 80, baseFeePct: 125},
+	FeeTierStandard: {tipPct: 100, baseFeePct: 200},
+	FeeTierFast:     {tipPct: 150, baseFeePct: 300},
+}
+
+func ParseFeeTier(s string) (FeeTier, error) {
+	if s == "" {
+		return FeeTierStandard, nil
+	}
+	tier := FeeTier(s)
+	if _, ok := tierMultipliers[tier]; !ok {
+		return "", ErrUnknownFeeTier
+	}
+	return tier, nil
+}
+
+// FeeOptions selects the fees of a transaction. GasFeeCap and GasTipCap, if set,
+// override the values of the tier.
+type FeeOptions struct {
+	Tier      FeeTier
+	GasFeeCap *big.Int
+	GasTipCap *big.Int
+}
+
+type FeeSuggestion struct {
+	GasTipCap     *big.Int
+	GasFeeCap     *big.Int
+	EstimatedCost *big.Int
+	MaxCost       *big.Int
+}
+
+type FeeSuggestions struct {
+	BaseFee  *big.Int
+	GasLimit uint64
+	Tiers    map[FeeTier]*FeeSuggestion
+}
+
+func (c *client) SuggestFees(ctx context.Context, sender, receiver common.Address, amount *big.Int) (*FeeSuggestions, error) {
+	ethClient, err := c.sdk.Extern().ConnectEthClient()
+	if err != nil {
+		return nil, err
+	}
+	defer ethClient.Close()
+
+	baseFee, suggestedTip, err := c.networkFees(ctx, ethClient)
+	if err != nil {
+		return nil, err
+	}
+
+	gasLimit, err := c.estimateGas(ctx, ethClient, sender, receiver, amount, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := &FeeSuggestions{
+		BaseFee:  baseFee,
+		GasLimit: gasLimit,
+		Tiers:    make(map[FeeTier]*FeeSuggestion, len(FeeTiers)),
+	}
+	gas := new(big.Int).SetUint64(gasLimit)
+	for _, tier := range FeeTiers {
+		feeCap, tip := tierFees(tier, baseFee, suggestedTip)
+
+		// the effective gas price is base fee + tip, but never more than the fee cap
+		price := new(big.Int).Add(baseFee, tip)
+		if price.Cmp(feeCap) > 0 {
+			price = feeCap
+		}
+
+		suggestions.Tiers[tier] = &FeeSuggestion{
+			GasTipCap:     tip,
+			GasFeeCap:     feeCap,
+			EstimatedCost: new(big.Int).Mul(gas, price),
+			MaxCost:       new(big.Int).Mul(gas, feeCap),
+		}
+	}
+	return suggestions, nil
+}
+
+// resolveFees returns the fee cap and tip cap selected by opts.
+func (c *client) resolveFees(ctx context.Context, ethClient *ethclient.Client, opts FeeOptions) (*big.Int, *big.Int, error) {
+	tier := opts.Tier
+	if tier == "" {
+		tier = FeeTierStandard
+	}
+	if _, ok := tierMultipliers[tier]; !ok {
+		return nil, nil, ErrUnknownFeeTier
+	}
+
+	var baseFee, suggestedTip *big.Int
+	if opts.GasFeeCap == nil || opts.GasTipCap == nil {
+		var err error
+		baseFee, suggestedTip, err = c.networkFees(ctx, ethClient)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return selectFees(tier, baseFee, suggestedTip, opts)
+}
+
+// selectFees returns the fees of tier with the caps given in opts in place. A fee
+// cap derived for a given tip covers that tip instead of the suggested one.
+func selectFees(tier FeeTier, baseFee, suggestedTip *big.Int, opts FeeOptions) (*big.Int, *big.Int, error) {
+	feeCap, tip := opts.GasFeeCap, opts.GasTipCap
+	switch {
+	case feeCap == nil && tip == nil:
+		feeCap, tip = tierFees(tier, baseFee, suggestedTip)
+	case feeCap == nil:
+		feeCap = new(big.Int).Add(tierBaseFee(tier, baseFee), tip)
+	case tip == nil:
+		_, tip = tierFees(tier, baseFee, suggestedTip)
+		if tip.Cmp(feeCap) > 0 {
+			tip = feeCap
+		}
+	}
+
+	if tip.Cmp(feeCap) > 0 {
+		return nil, nil, ErrInvalidFeeCaps
+	}
+	return feeCap, tip, nil
+}
+
+func (c *client) networkFees(ctx context.Context, ethClient *ethclient.Client) (*big.Int, *big.Int, error) {
+	head, err := ethClient.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get latest header: %w", err)
+	}
+	if head.BaseFee == nil {
+		return nil, nil, errors.New("latest header has no base fee")
+	}
+
+	tip, err := ethClient.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get gas tip cap: %w", err)
+	}
+	return head.BaseFee, tip, nil
+}
+
+func (c *client) estimateGas(ctx context.Context, ethClient *ethclient.Client, sender, receiver common.Address, amount *big.Int, data []byte) (uint64, error) {
+	gasLimit, err := ethClient.EstimateGas(ctx, ethereum.CallMsg{
+		From:  sender,
+		To:    &receiver,
+		Value: amount,
+		Data:  data,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+	return uint64(float64(gasLimit) * 1.5), nil // to prevent 'out of gas' error
+}
+
+func tierFees(tier FeeTier, baseFee, suggestedTip *big.Int) (*big.Int, *big.Int) {
+	m := tierMultipliers[tier]
+
+	tip := new(big.Int).Mul(suggestedTip, big.NewInt(m.tipPct))
+	tip.Div(tip, big.NewInt(100))
+
+	feeCap := tierBaseFee(tier, baseFee)
+	feeCap.Add(feeCap, tip)
+	return feeCap, tip
+}
+
+// tierBaseFee is the part of the fee cap of tier that covers the base fee.
+func tierBaseFee(tier FeeTier, baseFee *big.Int) *big.Int {
+	fee := new(big.Int).Mul(baseFee, big.NewInt(tierMultipliers[tier].baseFeePct))
+	return fee.Div(fee, big.NewInt(100))
+}
@@ -0,0 +1,68 @@
+package blockchain
+
+import (
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+func TestTierFees(t *testing.T) {
+	baseFee := big.NewInt(1000)
+	tip := big.NewInt(100)
+
+	feeCap, tipCap := tierFees(FeeTierSlow, baseFee, tip)
+	require.Equal(t, big.NewInt(80), tipCap)
+	require.Equal(t, big.NewInt(1330), feeCap)
+
+	feeCap, tipCap = tierFees(FeeTierStandard, baseFee, tip)
+	require.Equal(t, big.NewInt(100), tipCap)
+	require.Equal(t, big.NewInt(2100), feeCap)
+
+	feeCap, tipCap = tierFees(FeeTierFast, baseFee, tip)
+	require.Equal(t, big.NewInt(150), tipCap)
+	require.Equal(t, big.NewInt(3150), feeCap)
+}
+
+func TestSelectFees(t *testing.T) {
+	baseFee := big.NewInt(1000)
+	tip := big.NewInt(100)
+
+	feeCap, tipCap, err := selectFees(FeeTierStandard, baseFee, tip, FeeOptions{})
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(100), tipCap)
+	require.Equal(t, big.NewInt(2100), feeCap)
+
+	// the fee cap covers a given tip above the suggested fee cap
+	feeCap, tipCap, err = selectFees(FeeTierStandard, baseFee, tip, FeeOptions{GasTipCap: big.NewInt(5000)})
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(5000), tipCap)
+	require.Equal(t, big.NewInt(7000), feeCap)
+
+	// the suggested tip is capped by a given fee cap
+	feeCap, tipCap, err = selectFees(FeeTierFast, baseFee, tip, FeeOptions{GasFeeCap: big.NewInt(120)})
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(120), tipCap)
+	require.Equal(t, big.NewInt(120), feeCap)
+
+	// given caps are taken as they are
+	feeCap, tipCap, err = selectFees(FeeTierSlow, nil, nil, FeeOptions{GasFeeCap: big.NewInt(3000), GasTipCap: big.NewInt(200)})
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(200), tipCap)
+	require.Equal(t, big.NewInt(3000), feeCap)
+
+	_, _, err = selectFees(FeeTierSlow, nil, nil, FeeOptions{GasFeeCap: big.NewInt(100), GasTipCap: big.NewInt(200)})
+	require.ErrorIs(t, err, ErrInvalidFeeCaps)
+}
+
+func TestParseFeeTier(t *testing.T) {
+	tier, err := ParseFeeTier("")
+	require.NoError(t, err)
+	require.Equal(t, FeeTierStandard, tier)
+
+	tier, err = ParseFeeTier("fast")
+	require.NoError(t, err)
+	require.Equal(t, FeeTierFast, tier)
+
+	_, err = ParseFeeTier("ludicrous")
+	require.ErrorIs(t, err, ErrUnknownFeeTier)
+}
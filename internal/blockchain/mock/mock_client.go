@@ -12,7 +12,6 @@ package blockchain
 import (
 	blockchain "app/internal/blockchain"
 	context "context"
-	ecdsa "crypto/ecdsa"
 	big "math/big"
 	reflect "reflect"
 
@@ -45,6 +44,80 @@ func (m *MockClient) EXPECT() *MockClientMockRecorder {
 	return m.recorder
 }
 
+// BlockNumber mocks base method.
+func (m *MockClient) BlockNumber(ctx context.Context) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockNumber", ctx)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockNumber indicates an expected call of BlockNumber.
+func (mr *MockClientMockRecorder) BlockNumber(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockNumber", reflect.TypeOf((*MockClient)(nil).BlockNumber), ctx)
+}
+
+// CancelTransaction mocks base method.
+func (m *MockClient) CancelTransaction(ctx context.Context, signer blockchain.Signer, hash common.Hash, fees blockchain.FeeOptions) (*types.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelTransaction", ctx, signer, hash, fees)
+	ret0, _ := ret[0].(*types.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelTransaction indicates an expected call of CancelTransaction.
+func (mr *MockClientMockRecorder) CancelTransaction(ctx, signer, hash, fees any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelTransaction", reflect.TypeOf((*MockClient)(nil).CancelTransaction), ctx, signer, hash, fees)
+}
+
+// ChainID mocks base method.
+func (m *MockClient) ChainID() *big.Int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChainID")
+	ret0, _ := ret[0].(*big.Int)
+	return ret0
+}
+
+// ChainID indicates an expected call of ChainID.
+func (mr *MockClientMockRecorder) ChainID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChainID", reflect.TypeOf((*MockClient)(nil).ChainID))
+}
+
+// FindFILTransfers mocks base method.
+func (m *MockClient) FindFILTransfers(ctx context.Context, filter blockchain.TransferFilter, from, to uint64) ([]blockchain.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFILTransfers", ctx, filter, from, to)
+	ret0, _ := ret[0].([]blockchain.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFILTransfers indicates an expected call of FindFILTransfers.
+func (mr *MockClientMockRecorder) FindFILTransfers(ctx, filter, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFILTransfers", reflect.TypeOf((*MockClient)(nil).FindFILTransfers), ctx, filter, from, to)
+}
+
+// FindTokenTransfers mocks base method.
+func (m *MockClient) FindTokenTransfers(ctx context.Context, token common.Address, filter blockchain.TransferFilter, from, to uint64) ([]blockchain.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTokenTransfers", ctx, token, filter, from, to)
+	ret0, _ := ret[0].([]blockchain.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTokenTransfers indicates an expected call of FindTokenTransfers.
+func (mr *MockClientMockRecorder) FindTokenTransfers(ctx, token, filter, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTokenTransfers", reflect.TypeOf((*MockClient)(nil).FindTokenTransfers), ctx, token, filter, from, to)
+}
+
 // GetBalances mocks base method.
 func (m *MockClient) GetBalances(ctx context.Context, address common.Address) (*blockchain.WalletBalance, error) {
 	m.ctrl.T.Helper()
@@ -60,32 +133,296 @@ func (mr *MockClientMockRecorder) GetBalances(ctx, address any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalances", reflect.TypeOf((*MockClient)(nil).GetBalances), ctx, address)
 }
 
-// SubmitFILTransaction mocks base method.
-func (m *MockClient) SubmitFILTransaction(ctx context.Context, signer *ecdsa.PrivateKey, receiver common.Address, amount *big.Int) (*types.Transaction, error) {
+// GetTransaction mocks base method.
+func (m *MockClient) GetTransaction(ctx context.Context, hash common.Hash) (*blockchain.TransactionInfo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SubmitFILTransaction", ctx, signer, receiver, amount)
+	ret := m.ctrl.Call(m, "GetTransaction", ctx, hash)
+	ret0, _ := ret[0].(*blockchain.TransactionInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransaction indicates an expected call of GetTransaction.
+func (mr *MockClientMockRecorder) GetTransaction(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransaction", reflect.TypeOf((*MockClient)(nil).GetTransaction), ctx, hash)
+}
+
+// GetTransactionReceipt mocks base method.
+func (m *MockClient) GetTransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionReceipt", ctx, hash)
+	ret0, _ := ret[0].(*types.Receipt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionReceipt indicates an expected call of GetTransactionReceipt.
+func (mr *MockClientMockRecorder) GetTransactionReceipt(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionReceipt", reflect.TypeOf((*MockClient)(nil).GetTransactionReceipt), ctx, hash)
+}
+
+// NonceAt mocks base method.
+func (m *MockClient) NonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NonceAt", ctx, account)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NonceAt indicates an expected call of NonceAt.
+func (mr *MockClientMockRecorder) NonceAt(ctx, account any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NonceAt", reflect.TypeOf((*MockClient)(nil).NonceAt), ctx, account)
+}
+
+// PoolAllowance mocks base method.
+func (m *MockClient) PoolAllowance(ctx context.Context, owner common.Address) (*big.Int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PoolAllowance", ctx, owner)
+	ret0, _ := ret[0].(*big.Int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PoolAllowance indicates an expected call of PoolAllowance.
+func (mr *MockClientMockRecorder) PoolAllowance(ctx, owner any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PoolAllowance", reflect.TypeOf((*MockClient)(nil).PoolAllowance), ctx, owner)
+}
+
+// PrepareFILTransaction mocks base method.
+func (m *MockClient) PrepareFILTransaction(ctx context.Context, sender, receiver common.Address, amount *big.Int, fees blockchain.FeeOptions) (*types.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PrepareFILTransaction", ctx, sender, receiver, amount, fees)
 	ret0, _ := ret[0].(*types.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// SubmitFILTransaction indicates an expected call of SubmitFILTransaction.
-func (mr *MockClientMockRecorder) SubmitFILTransaction(ctx, signer, receiver, amount any) *gomock.Call {
+// PrepareFILTransaction indicates an expected call of PrepareFILTransaction.
+func (mr *MockClientMockRecorder) PrepareFILTransaction(ctx, sender, receiver, amount, fees any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrepareFILTransaction", reflect.TypeOf((*MockClient)(nil).PrepareFILTransaction), ctx, sender, receiver, amount, fees)
+}
+
+// PreviewDeposit mocks base method.
+func (m *MockClient) PreviewDeposit(ctx context.Context, amount *big.Int) (*big.Int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreviewDeposit", ctx, amount)
+	ret0, _ := ret[0].(*big.Int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreviewDeposit indicates an expected call of PreviewDeposit.
+func (mr *MockClientMockRecorder) PreviewDeposit(ctx, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreviewDeposit", reflect.TypeOf((*MockClient)(nil).PreviewDeposit), ctx, amount)
+}
+
+// PreviewPoolExit mocks base method.
+func (m *MockClient) PreviewPoolExit(ctx context.Context, exit blockchain.PoolExit) (*big.Int, *big.Int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreviewPoolExit", ctx, exit)
+	ret0, _ := ret[0].(*big.Int)
+	ret1, _ := ret[1].(*big.Int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// PreviewPoolExit indicates an expected call of PreviewPoolExit.
+func (mr *MockClientMockRecorder) PreviewPoolExit(ctx, exit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreviewPoolExit", reflect.TypeOf((*MockClient)(nil).PreviewPoolExit), ctx, exit)
+}
+
+// ReleaseNonce mocks base method.
+func (m *MockClient) ReleaseNonce(sender common.Address, nonce uint64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ReleaseNonce", sender, nonce)
+}
+
+// ReleaseNonce indicates an expected call of ReleaseNonce.
+func (mr *MockClientMockRecorder) ReleaseNonce(sender, nonce any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitFILTransaction", reflect.TypeOf((*MockClient)(nil).SubmitFILTransaction), ctx, signer, receiver, amount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseNonce", reflect.TypeOf((*MockClient)(nil).ReleaseNonce), sender, nonce)
 }
 
-// SubmitIFILTransaction mocks base method.
-func (m *MockClient) SubmitIFILTransaction(ctx context.Context, signer *ecdsa.PrivateKey, receiver common.Address, amount *big.Int) (*types.Transaction, error) {
+// ResetNonces mocks base method.
+func (m *MockClient) ResetNonces(sender common.Address) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SubmitIFILTransaction", ctx, signer, receiver, amount)
+	m.ctrl.Call(m, "ResetNonces", sender)
+}
+
+// ResetNonces indicates an expected call of ResetNonces.
+func (mr *MockClientMockRecorder) ResetNonces(sender any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetNonces", reflect.TypeOf((*MockClient)(nil).ResetNonces), sender)
+}
+
+// SendTransaction mocks base method.
+func (m *MockClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendTransaction", ctx, tx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendTransaction indicates an expected call of SendTransaction.
+func (mr *MockClientMockRecorder) SendTransaction(ctx, tx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendTransaction", reflect.TypeOf((*MockClient)(nil).SendTransaction), ctx, tx)
+}
+
+// SignFILTransaction mocks base method.
+func (m *MockClient) SignFILTransaction(ctx context.Context, signer blockchain.Signer, receiver common.Address, amount *big.Int, fees blockchain.FeeOptions) (*types.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignFILTransaction", ctx, signer, receiver, amount, fees)
+	ret0, _ := ret[0].(*types.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignFILTransaction indicates an expected call of SignFILTransaction.
+func (mr *MockClientMockRecorder) SignFILTransaction(ctx, signer, receiver, amount, fees any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignFILTransaction", reflect.TypeOf((*MockClient)(nil).SignFILTransaction), ctx, signer, receiver, amount, fees)
+}
+
+// SignIFILTransaction mocks base method.
+func (m *MockClient) SignIFILTransaction(ctx context.Context, signer blockchain.Signer, receiver common.Address, amount *big.Int, fees blockchain.FeeOptions) (*types.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignIFILTransaction", ctx, signer, receiver, amount, fees)
+	ret0, _ := ret[0].(*types.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignIFILTransaction indicates an expected call of SignIFILTransaction.
+func (mr *MockClientMockRecorder) SignIFILTransaction(ctx, signer, receiver, amount, fees any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignIFILTransaction", reflect.TypeOf((*MockClient)(nil).SignIFILTransaction), ctx, signer, receiver, amount, fees)
+}
+
+// SignPoolApproval mocks base method.
+func (m *MockClient) SignPoolApproval(ctx context.Context, signer blockchain.Signer, amount *big.Int, fees blockchain.FeeOptions) (*types.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignPoolApproval", ctx, signer, amount, fees)
+	ret0, _ := ret[0].(*types.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignPoolApproval indicates an expected call of SignPoolApproval.
+func (mr *MockClientMockRecorder) SignPoolApproval(ctx, signer, amount, fees any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignPoolApproval", reflect.TypeOf((*MockClient)(nil).SignPoolApproval), ctx, signer, amount, fees)
+}
+
+// SignPoolDeposit mocks base method.
+func (m *MockClient) SignPoolDeposit(ctx context.Context, signer blockchain.Signer, amount *big.Int, fees blockchain.FeeOptions) (*types.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignPoolDeposit", ctx, signer, amount, fees)
+	ret0, _ := ret[0].(*types.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignPoolDeposit indicates an expected call of SignPoolDeposit.
+func (mr *MockClientMockRecorder) SignPoolDeposit(ctx, signer, amount, fees any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignPoolDeposit", reflect.TypeOf((*MockClient)(nil).SignPoolDeposit), ctx, signer, amount, fees)
+}
+
+// SignPoolExit mocks base method.
+func (m *MockClient) SignPoolExit(ctx context.Context, signer blockchain.Signer, exit blockchain.PoolExit, fees blockchain.FeeOptions) (*types.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignPoolExit", ctx, signer, exit, fees)
+	ret0, _ := ret[0].(*types.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignPoolExit indicates an expected call of SignPoolExit.
+func (mr *MockClientMockRecorder) SignPoolExit(ctx, signer, exit, fees any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignPoolExit", reflect.TypeOf((*MockClient)(nil).SignPoolExit), ctx, signer, exit, fees)
+}
+
+// SignTokenTransaction mocks base method.
+func (m *MockClient) SignTokenTransaction(ctx context.Context, signer blockchain.Signer, token, receiver common.Address, amount *big.Int, fees blockchain.FeeOptions) (*types.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignTokenTransaction", ctx, signer, token, receiver, amount, fees)
 	ret0, _ := ret[0].(*types.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// SubmitIFILTransaction indicates an expected call of SubmitIFILTransaction.
-func (mr *MockClientMockRecorder) SubmitIFILTransaction(ctx, signer, receiver, amount any) *gomock.Call {
+// SignTokenTransaction indicates an expected call of SignTokenTransaction.
+func (mr *MockClientMockRecorder) SignTokenTransaction(ctx, signer, token, receiver, amount, fees any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignTokenTransaction", reflect.TypeOf((*MockClient)(nil).SignTokenTransaction), ctx, signer, token, receiver, amount, fees)
+}
+
+// SpeedUpTransaction mocks base method.
+func (m *MockClient) SpeedUpTransaction(ctx context.Context, signer blockchain.Signer, hash common.Hash, fees blockchain.FeeOptions) (*types.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SpeedUpTransaction", ctx, signer, hash, fees)
+	ret0, _ := ret[0].(*types.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SpeedUpTransaction indicates an expected call of SpeedUpTransaction.
+func (mr *MockClientMockRecorder) SpeedUpTransaction(ctx, signer, hash, fees any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SpeedUpTransaction", reflect.TypeOf((*MockClient)(nil).SpeedUpTransaction), ctx, signer, hash, fees)
+}
+
+// SuggestFees mocks base method.
+func (m *MockClient) SuggestFees(ctx context.Context, sender, receiver common.Address, amount *big.Int) (*blockchain.FeeSuggestions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SuggestFees", ctx, sender, receiver, amount)
+	ret0, _ := ret[0].(*blockchain.FeeSuggestions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SuggestFees indicates an expected call of SuggestFees.
+func (mr *MockClientMockRecorder) SuggestFees(ctx, sender, receiver, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuggestFees", reflect.TypeOf((*MockClient)(nil).SuggestFees), ctx, sender, receiver, amount)
+}
+
+// TokenBalance mocks base method.
+func (m *MockClient) TokenBalance(ctx context.Context, token, account common.Address) (*big.Int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TokenBalance", ctx, token, account)
+	ret0, _ := ret[0].(*big.Int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TokenBalance indicates an expected call of TokenBalance.
+func (mr *MockClientMockRecorder) TokenBalance(ctx, token, account any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TokenBalance", reflect.TypeOf((*MockClient)(nil).TokenBalance), ctx, token, account)
+}
+
+// TokenDecimals mocks base method.
+func (m *MockClient) TokenDecimals(ctx context.Context, token common.Address) (uint8, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TokenDecimals", ctx, token)
+	ret0, _ := ret[0].(uint8)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TokenDecimals indicates an expected call of TokenDecimals.
+func (mr *MockClientMockRecorder) TokenDecimals(ctx, token any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitIFILTransaction", reflect.TypeOf((*MockClient)(nil).SubmitIFILTransaction), ctx, signer, receiver, amount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TokenDecimals", reflect.TypeOf((*MockClient)(nil).TokenDecimals), ctx, token)
 }
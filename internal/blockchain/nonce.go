@@ -0,0 +1,170 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type NonceSource interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// NonceStore knows the nonces of transactions which are signed but not broadcast
+// yet. The node does not know them, so they would be handed out again after a restart.
+type NonceStore interface {
+	// MaxQueuedNonce returns the highest nonce queued for sender, nil if there is none.
+	MaxQueuedNonce(ctx context.Context, sender string) (*uint64, error)
+}
+
+// NonceManager hands out nonces per sender so that concurrent submissions from
+// the same address never get the same nonce. Nonces of failed broadcasts are
+// released and handed out again before new ones.
+type NonceManager struct {
+	mu      sync.Mutex
+	store   NonceStore
+	senders map[common.Address]*senderNonces
+}
+
+type senderNonces struct {
+	mu       sync.Mutex
+	synced   bool
+	next     uint64
+	released []uint64
+}
+
+// NewNonceManager creates a nonce manager. Without a store only the chain is
+// consulted when a sender is synced.
+func NewNonceManager(store NonceStore) *NonceManager {
+	return &NonceManager{store: store, senders: make(map[common.Address]*senderNonces)}
+}
+
+func (m *NonceManager) sender(address common.Address) *senderNonces {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sn, ok := m.senders[address]
+	if !ok {
+		sn = &senderNonces{}
+		m.senders[address] = sn
+	}
+	return sn
+}
+
+// Next reserves the next nonce of sender. The chain is consulted on every call,
+// so nonces used outside of this manager are skipped. The first call for a sender
+// also skips the nonces of its queued transactions.
+func (m *NonceManager) Next(ctx context.Context, source NonceSource, sender common.Address) (uint64, error) {
+	sn := m.sender(sender)
+	sn.mu.Lock()
+	defer sn.mu.Unlock()
+
+	pending, err := source.PendingNonceAt(ctx, sender)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get nonce: %w", err)
+	}
+
+	if !sn.synced {
+		next, err := m.nextUnqueued(ctx, sender)
+		if err != nil {
+			return 0, err
+		}
+		sn.next = max(pending, next)
+		sn.synced = true
+	}
+	if pending > sn.next {
+		sn.next = pending
+	}
+
+	// released nonces below the pending one were consumed by other transactions meanwhile
+	for len(sn.released) > 0 && sn.released[0] < pending {
+		sn.released = sn.released[1:]
+	}
+	if len(sn.released) > 0 {
+		nonce := sn.released[0]
+		sn.released = sn.released[1:]
+		return nonce, nil
+	}
+
+	nonce := sn.next
+	sn.next++
+	return nonce, nil
+}
+
+// nextUnqueued returns the nonce after the queued transactions of sender, 0 if there are none.
+func (m *NonceManager) nextUnqueued(ctx context.Context, sender common.Address) (uint64, error) {
+	if m.store == nil {
+		return 0, nil
+	}
+
+	queued, err := m.store.MaxQueuedNonce(ctx, strings.ToLower(sender.Hex()))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get queued nonce: %w", err)
+	}
+	if queued == nil {
+		return 0, nil
+	}
+	return *queued + 1, nil
+}
+
+// Release returns a nonce which was reserved but never reached the mempool.
+func (m *NonceManager) Release(sender common.Address, nonce uint64) {
+	sn := m.sender(sender)
+	sn.mu.Lock()
+	defer sn.mu.Unlock()
+
+	if !sn.synced || nonce >= sn.next {
+		return
+	}
+
+	if nonce == sn.next-1 {
+		sn.next--
+		// trailing released nonces are now directly below next as well
+		for len(sn.released) > 0 && sn.released[len(sn.released)-1] == sn.next-1 {
+			sn.released = sn.released[:len(sn.released)-1]
+			sn.next--
+		}
+		return
+	}
+
+	idx := sort.Search(len(sn.released), func(i int) bool { return sn.released[i] >= nonce })
+	if idx < len(sn.released) && sn.released[idx] == nonce {
+		return
+	}
+	sn.released = append(sn.released, 0)
+	copy(sn.released[idx+1:], sn.released[idx:])
+	sn.released[idx] = nonce
+}
+
+// Reset drops the local state of sender, the next call to Next resyncs with the chain
+// and the store.
+func (m *NonceManager) Reset(sender common.Address) {
+	sn := m.sender(sender)
+	sn.mu.Lock()
+	defer sn.mu.Unlock()
+
+	sn.synced = false
+	sn.next = 0
+	sn.released = nil
+}
+
+// IsNonceConflict reports whether a broadcast failed because the nonce is already used.
+func IsNonceConflict(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "nonce too low") || strings.Contains(msg, "replacement transaction underpriced")
+}
+
+// IsKnownTransaction reports whether a broadcast failed because the node already has the transaction.
+func IsKnownTransaction(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "already known") || strings.Contains(msg, "already in mpool")
+}
@@ -0,0 +1,149 @@
+package blockchain
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+)
+
+type staticNonceSource struct {
+	mu      sync.Mutex
+	pending uint64
+}
+
+func (s *staticNonceSource) PendingNonceAt(context.Context, common.Address) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pending, nil
+}
+
+func (s *staticNonceSource) set(pending uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending = pending
+}
+
+func TestNonceManager(t *testing.T) {
+	ctx := context.Background()
+	sender := common.HexToAddress("0xa512eb36e162bfb0e9f55b56bc2a070ca0d3ecd7")
+	source := &staticNonceSource{pending: 10}
+	m := NewNonceManager(nil)
+
+	next := func() uint64 {
+		nonce, err := m.Next(ctx, source, sender)
+		require.NoError(t, err)
+		return nonce
+	}
+
+	// chain does not see reserved nonces until they are broadcast
+	require.Equal(t, uint64(10), next())
+	require.Equal(t, uint64(11), next())
+	require.Equal(t, uint64(12), next())
+
+	// releasing the latest nonce rolls back, releasing an older one leaves a gap to refill
+	m.Release(sender, 12)
+	m.Release(sender, 10)
+	require.Equal(t, uint64(10), next())
+	require.Equal(t, uint64(12), next())
+
+	// nonces used outside of the manager are skipped
+	source.set(20)
+	require.Equal(t, uint64(20), next())
+
+	// released gaps consumed by other transactions are dropped
+	m.Release(sender, 20)
+	m.Release(sender, 15)
+	source.set(21)
+	require.Equal(t, uint64(21), next())
+
+	// reset resyncs with the chain
+	m.Reset(sender)
+	source.set(18)
+	require.Equal(t, uint64(18), next())
+}
+
+type queuedNonceStore struct {
+	queued map[string]uint64
+}
+
+func (s *queuedNonceStore) MaxQueuedNonce(_ context.Context, sender string) (*uint64, error) {
+	nonce, ok := s.queued[sender]
+	if !ok {
+		return nil, nil
+	}
+	return &nonce, nil
+}
+
+func TestNonceManager_QueuedAfterRestart(t *testing.T) {
+	ctx := context.Background()
+	sender := common.HexToAddress("0xa512eb36e162bfb0e9f55b56bc2a070ca0d3ecd7")
+	other := common.HexToAddress("0x5d5d4d04b70bfe49ad7aac8c4454536070daf180")
+	source := &staticNonceSource{pending: 10}
+
+	// nonces 10..12 of sender were queued before the restart, the node does not know them
+	m := NewNonceManager(&queuedNonceStore{queued: map[string]uint64{"0xa512eb36e162bfb0e9f55b56bc2a070ca0d3ecd7": 12}})
+
+	nonce, err := m.Next(ctx, source, sender)
+	require.NoError(t, err)
+	require.Equal(t, uint64(13), nonce)
+
+	nonce, err = m.Next(ctx, source, other)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), nonce)
+
+	// queued nonces the chain already moved past are skipped
+	source.set(20)
+	m.Reset(sender)
+	nonce, err = m.Next(ctx, source, sender)
+	require.NoError(t, err)
+	require.Equal(t, uint64(20), nonce)
+}
+
+func TestNonceManager_Concurrent(t *testing.T) {
+	const workers = 50
+	ctx := context.Background()
+	sender := common.HexToAddress("0xa512eb36e162bfb0e9f55b56bc2a070ca0d3ecd7")
+	source := &staticNonceSource{}
+	m := NewNonceManager(nil)
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		nonces = make(map[uint64]struct{})
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			nonce, err := m.Next(ctx, source, sender)
+			require.NoError(t, err)
+
+			mu.Lock()
+			defer mu.Unlock()
+			nonces[nonce] = struct{}{}
+		}()
+	}
+	wg.Wait()
+
+	require.Len(t, nonces, workers)
+	for i := uint64(0); i < workers; i++ {
+		require.Contains(t, nonces, i)
+	}
+}
+
+func TestIsNonceConflict(t *testing.T) {
+	require.True(t, IsNonceConflict(errors.New("minimum expected nonce is 5: message nonce too low")))
+	require.True(t, IsNonceConflict(errors.New("replacement transaction underpriced")))
+	require.False(t, IsNonceConflict(errors.New("insufficient funds")))
+	require.False(t, IsNonceConflict(nil))
+}
+
+func TestIsKnownTransaction(t *testing.T) {
+	require.True(t, IsKnownTransaction(errors.New("failed to send tx: already known")))
+	require.True(t, IsKnownTransaction(errors.New("message already in mpool")))
+	require.False(t, IsKnownTransaction(errors.New("nonce too low")))
+	require.False(t, IsKnownTransaction(nil))
+}
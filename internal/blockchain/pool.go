@@ -0,0 +1,148 @@
+package blockchain
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/v2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/glifio/go-pools/abigen"
+	"go.uber.org/zap"
+	"math/big"
+)
+
+// PoolExit selects how iFIL leaves the pool: Redeem burns exactly Amount iFIL,
+// otherwise exactly Amount attoFIL is withdrawn.
+type PoolExit struct {
+	Redeem bool
+	Amount *big.Int
+}
+
+// SignPoolDeposit signs a deposit of amount attoFIL into the Infinity Pool, which mints
+// iFIL to the signer, with the next nonce of the signer without broadcasting it. The
+// nonce stays reserved until ReleaseNonce is called.
+func (c *client) SignPoolDeposit(ctx context.Context, signer Signer, amount *big.Int, fees FeeOptions) (*types.Transaction, error) {
+	acts := c.sdk.Act()
+	return c.signPoolCall(ctx, signer, fees, func(_ *ethclient.Client, auth *bind.TransactOpts) (*types.Transaction, error) {
+		return acts.InfPoolDepositFIL(ctx, auth, amount)
+	})
+}
+
+// PreviewDeposit returns the iFIL minted for a deposit of amount attoFIL at the current exchange rate.
+func (c *client) PreviewDeposit(ctx context.Context, amount *big.Int) (*big.Int, error) {
+	var shares *big.Int
+	err := c.callPool(ctx, func(pool *abigen.InfinityPoolCaller, opts *bind.CallOpts) (err error) {
+		shares, err = pool.PreviewDeposit(opts, amount)
+		return err
+	})
+	return shares, err
+}
+
+// PreviewPoolExit returns the iFIL burned and the attoFIL paid out by exit at the
+// current exchange rate.
+func (c *client) PreviewPoolExit(ctx context.Context, exit PoolExit) (*big.Int, *big.Int, error) {
+	shares, assets := exit.Amount, exit.Amount
+	err := c.callPool(ctx, func(pool *abigen.InfinityPoolCaller, opts *bind.CallOpts) (err error) {
+		if exit.Redeem {
+			assets, err = pool.PreviewRedeem(opts, exit.Amount)
+		} else {
+			shares, err = pool.PreviewWithdraw(opts, exit.Amount)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return shares, assets, nil
+}
+
+// PoolAllowance returns the iFIL of owner the pool may burn on its behalf.
+func (c *client) PoolAllowance(ctx context.Context, owner common.Address) (*big.Int, error) {
+	ethClient, err := c.sdk.Extern().ConnectEthClient()
+	if err != nil {
+		return nil, err
+	}
+	defer ethClient.Close()
+
+	queries := c.sdk.Query()
+	ifil, err := abigen.NewPoolTokenCaller(queries.IFIL(), ethClient)
+	if err != nil {
+		return nil, err
+	}
+	return ifil.Allowance(&bind.CallOpts{Context: ctx}, owner, queries.InfinityPool())
+}
+
+// SignPoolApproval signs an iFIL approval of amount for the pool with the next nonce
+// of the signer without broadcasting it. The nonce stays reserved until ReleaseNonce is called.
+func (c *client) SignPoolApproval(ctx context.Context, signer Signer, amount *big.Int, fees FeeOptions) (*types.Transaction, error) {
+	queries := c.sdk.Query()
+	return c.signPoolCall(ctx, signer, fees, func(ethClient *ethclient.Client, auth *bind.TransactOpts) (*types.Transaction, error) {
+		ifil, err := abigen.NewPoolTokenTransactor(queries.IFIL(), ethClient)
+		if err != nil {
+			return nil, err
+		}
+		return ifil.Approve(auth, queries.InfinityPool(), amount)
+	})
+}
+
+// SignPoolExit signs a redemption or withdrawal of the signer's iFIL for FIL paid to
+// the signer, with the next nonce of the signer without broadcasting it. The nonce
+// stays reserved until ReleaseNonce is called.
+func (c *client) SignPoolExit(ctx context.Context, signer Signer, exit PoolExit, fees FeeOptions) (*types.Transaction, error) {
+	sender := signer.Address()
+	return c.signPoolCall(ctx, signer, fees, func(ethClient *ethclient.Client, auth *bind.TransactOpts) (*types.Transaction, error) {
+		pool, err := abigen.NewInfinityPoolTransactor(c.sdk.Query().InfinityPool(), ethClient)
+		if err != nil {
+			return nil, err
+		}
+		if exit.Redeem {
+			return pool.RedeemF(auth, exit.Amount, sender, sender)
+		}
+		return pool.WithdrawF(auth, exit.Amount, sender, sender)
+	})
+}
+
+// signPoolCall lets call sign a pool operation with the next nonce of the signer
+// without sending it.
+func (c *client) signPoolCall(ctx context.Context, signer Signer, fees FeeOptions, call func(ethClient *ethclient.Client, auth *bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, error) {
+	ethClient, err := c.sdk.Extern().ConnectEthClient()
+	if err != nil {
+		return nil, err
+	}
+	defer ethClient.Close()
+
+	chainID, err := ethClient.NetworkID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	gasFeeCap, gasTipCap, err := c.resolveFees(ctx, ethClient, fees)
+	if err != nil {
+		return nil, err
+	}
+
+	sender := signer.Address()
+	tx, err := c.signWithNonce(ctx, ethClient, sender, func(nonce uint64) (*types.Transaction, error) {
+		return call(ethClient, transactOpts(ctx, signer, chainID, nonce, gasFeeCap, gasTipCap))
+	})
+	if err != nil {
+		c.logger.Error("failed to sign pool transaction", zap.Error(err), zap.String("sender_address", sender.Hex()))
+		return nil, err
+	}
+	return tx, nil
+}
+
+// callPool runs view calls against the Infinity Pool.
+func (c *client) callPool(ctx context.Context, call func(pool *abigen.InfinityPoolCaller, opts *bind.CallOpts) error) error {
+	ethClient, err := c.sdk.Extern().ConnectEthClient()
+	if err != nil {
+		return err
+	}
+	defer ethClient.Close()
+
+	pool, err := abigen.NewInfinityPoolCaller(c.sdk.Query().InfinityPool(), ethClient)
+	if err != nil {
+		return err
+	}
+	return call(pool, &bind.CallOpts{Context: ctx})
+}
@@ -0,0 +1,145 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/pkg/errors"
+	"math/big"
+)
+
+// Lotus only accepts a replacement if its gas premium is at least 25% higher than the
+// original's. Fees are bumped by 30% so the replacement still clears that minimum after
+// the premium is rounded, and it is not rejected by nodes requiring a slightly higher bump.
+const replacementFeeBumpPct = 130
+
+var (
+	ErrTxNotPending   = errors.New("transaction is not pending")
+	ErrSignerMismatch = errors.New("signer is not the sender of the transaction")
+	ErrFeeBumpTooLow  = errors.New("replacement fees must be at least 30% higher than the original ones")
+)
+
+// SpeedUpTransaction signs a copy of a pending transaction with the same nonce and
+// higher fees. Broadcasting it is up to the caller.
+func (c *client) SpeedUpTransaction(ctx context.Context, signer Signer, hash common.Hash, fees FeeOptions) (*types.Transaction, error) {
+	return c.replaceTransaction(ctx, signer, hash, fees, func(_ *ethclient.Client, original *types.Transaction) (*types.DynamicFeeTx, error) {
+		return &types.DynamicFeeTx{
+			Gas:   original.Gas(),
+			To:    original.To(),
+			Value: original.Value(),
+			Data:  original.Data(),
+		}, nil
+	})
+}
+
+// CancelTransaction signs a zero-value transfer from the sender to itself which
+// replaces a pending transaction. Broadcasting it is up to the caller.
+func (c *client) CancelTransaction(ctx context.Context, signer Signer, hash common.Hash, fees FeeOptions) (*types.Transaction, error) {
+	sender := signer.Address()
+	return c.replaceTransaction(ctx, signer, hash, fees, func(ethClient *ethclient.Client, _ *types.Transaction) (*types.DynamicFeeTx, error) {
+		gasLimit, err := c.estimateGas(ctx, ethClient, sender, sender, big.NewInt(0), nil)
+		if err != nil {
+			return nil, err
+		}
+		return &types.DynamicFeeTx{
+			Gas:   gasLimit,
+			To:    &sender,
+			Value: big.NewInt(0),
+		}, nil
+	})
+}
+
+// replaceTransaction signs the transaction returned by build with the nonce of
+// the pending transaction hash and fees bumped over the original ones.
+func (c *client) replaceTransaction(ctx context.Context, signer Signer, hash common.Hash, fees FeeOptions, build func(*ethclient.Client, *types.Transaction) (*types.DynamicFeeTx, error)) (*types.Transaction, error) {
+	ethClient, err := c.sdk.Extern().ConnectEthClient()
+	if err != nil {
+		return nil, err
+	}
+	defer ethClient.Close()
+
+	chainID, err := ethClient.NetworkID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	original, err := c.pendingTransaction(ctx, ethClient, chainID, signer.Address(), hash)
+	if err != nil {
+		return nil, err
+	}
+
+	gasFeeCap, gasTipCap, err := c.replacementFees(ctx, ethClient, original, fees)
+	if err != nil {
+		return nil, err
+	}
+
+	txData, err := build(ethClient, original)
+	if err != nil {
+		return nil, err
+	}
+	txData.ChainID = chainID
+	txData.Nonce = original.Nonce()
+	txData.GasFeeCap = gasFeeCap
+	txData.GasTipCap = gasTipCap
+	return signer.SignTx(ctx, types.NewTx(txData), chainID)
+}
+
+func (c *client) pendingTransaction(ctx context.Context, ethClient *ethclient.Client, chainID *big.Int, sender common.Address, hash common.Hash) (*types.Transaction, error) {
+	tx, isPending, err := ethClient.TransactionByHash(ctx, hash)
+	if err != nil {
+		if errors.Is(err, ethereum.NotFound) {
+			return nil, ErrTxNotPending
+		}
+		return nil, fmt.Errorf("failed to get tx: %w", err)
+	}
+	if !isPending {
+		return nil, ErrTxNotPending
+	}
+
+	from, err := types.Sender(types.LatestSignerForChainID(chainID), tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover tx sender: %w", err)
+	}
+	if from != sender {
+		return nil, ErrSignerMismatch
+	}
+	return tx, nil
+}
+
+// replacementFees returns fees selected by opts, raised to the minimum bump over
+// the original's fees. Explicit caps below that minimum are rejected.
+func (c *client) replacementFees(ctx context.Context, ethClient *ethclient.Client, original *types.Transaction, opts FeeOptions) (*big.Int, *big.Int, error) {
+	gasFeeCap, gasTipCap, err := c.resolveFees(ctx, ethClient, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	minFeeCap := bumpFee(original.GasFeeCap())
+	minTipCap := bumpFee(original.GasTipCap())
+	if (opts.GasFeeCap != nil && opts.GasFeeCap.Cmp(minFeeCap) < 0) || (opts.GasTipCap != nil && opts.GasTipCap.Cmp(minTipCap) < 0) {
+		return nil, nil, ErrFeeBumpTooLow
+	}
+
+	if gasFeeCap.Cmp(minFeeCap) < 0 {
+		gasFeeCap = minFeeCap
+	}
+	if gasTipCap.Cmp(minTipCap) < 0 {
+		gasTipCap = minTipCap
+	}
+	if gasTipCap.Cmp(gasFeeCap) > 0 {
+		if opts.GasFeeCap != nil {
+			return nil, nil, ErrInvalidFeeCaps
+		}
+		gasFeeCap = new(big.Int).Set(gasTipCap)
+	}
+	return gasFeeCap, gasTipCap, nil
+}
+
+func bumpFee(fee *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(replacementFeeBumpPct))
+	bumped.Div(bumped, big.NewInt(100))
+	return bumped.Add(bumped, big.NewInt(1))
+}
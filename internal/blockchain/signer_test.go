@@ -0,0 +1,48 @@
+package blockchain
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+func TestLocalSigner(t *testing.T) {
+	ctx := context.Background()
+	chainID := big.NewInt(int64(testNet))
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	signer := NewLocalSigner(key)
+	require.Equal(t, crypto.PubkeyToAddress(key.PublicKey), signer.Address())
+
+	receiver := common.HexToAddress("0xa512eb36e162bfb0e9f55b56bc2a070ca0d3ecd7")
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     3,
+		GasTipCap: big.NewInt(1e9),
+		GasFeeCap: big.NewInt(3e9),
+		Gas:       21000,
+		To:        &receiver,
+		Value:     big.NewInt(1),
+	})
+
+	signedTx, err := signer.SignTx(ctx, tx, chainID)
+	require.NoError(t, err)
+
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signedTx)
+	require.NoError(t, err)
+	require.Equal(t, signer.Address(), sender)
+
+	hash := crypto.Keccak256Hash([]byte("glif"))
+	sig, err := signer.SignHash(ctx, hash)
+	require.NoError(t, err)
+
+	pubKey, err := crypto.SigToPub(hash.Bytes(), sig)
+	require.NoError(t, err)
+	require.Equal(t, signer.Address(), crypto.PubkeyToAddress(*pubKey))
+}
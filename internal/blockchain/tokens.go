@@ -0,0 +1,125 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.uber.org/zap"
+	"math/big"
+	"strings"
+)
+
+const erc20JSON = `[
+	{"type":"function","name":"allowance","stateMutability":"view","inputs":[{"name":"owner","type":"address"},{"name":"spender","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"approve","stateMutability":"nonpayable","inputs":[{"name":"spender","type":"address"},{"name":"value","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"decimals","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint8"}]},
+	{"type":"function","name":"transfer","stateMutability":"nonpayable","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]}
+]`
+
+var erc20ABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(erc20JSON))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+// TokenBalance returns the balance of account in the smallest unit of the ERC-20 token.
+func (c *client) TokenBalance(ctx context.Context, token, account common.Address) (*big.Int, error) {
+	ethClient, err := c.sdk.Extern().ConnectEthClient()
+	if err != nil {
+		return nil, err
+	}
+	defer ethClient.Close()
+
+	out, err := callContract(ctx, ethClient, erc20ABI, token, "balanceOf", account)
+	if err != nil {
+		return nil, err
+	}
+	balance, ok := out.(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("unexpected balanceOf result %T", out)
+	}
+	return balance, nil
+}
+
+// TokenDecimals reads the decimals of an ERC-20 token, it fails for contracts
+// which do not implement it.
+func (c *client) TokenDecimals(ctx context.Context, token common.Address) (uint8, error) {
+	ethClient, err := c.sdk.Extern().ConnectEthClient()
+	if err != nil {
+		return 0, err
+	}
+	defer ethClient.Close()
+
+	out, err := callContract(ctx, ethClient, erc20ABI, token, "decimals")
+	if err != nil {
+		return 0, err
+	}
+	decimals, ok := out.(uint8)
+	if !ok {
+		return 0, fmt.Errorf("unexpected decimals result %T", out)
+	}
+	return decimals, nil
+}
+
+// SignTokenTransaction signs a transfer(address,uint256) call of the ERC-20 token with
+// the next nonce of the signer without broadcasting it. The nonce stays reserved until
+// ReleaseNonce is called.
+func (c *client) SignTokenTransaction(ctx context.Context, signer Signer, token, receiver common.Address, amount *big.Int, fees FeeOptions) (*types.Transaction, error) {
+	ethClient, err := c.sdk.Extern().ConnectEthClient()
+	if err != nil {
+		return nil, err
+	}
+	defer ethClient.Close()
+
+	chainID, err := ethClient.NetworkID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := erc20ABI.Pack("transfer", receiver, amount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode token transfer: %w", err)
+	}
+
+	sender := signer.Address()
+	tx, err := c.signWithNonce(ctx, ethClient, sender, func(nonce uint64) (*types.Transaction, error) {
+		tx, err := c.buildTransaction(ctx, ethClient, chainID, nonce, sender, token, big.NewInt(0), data, fees)
+		if err != nil {
+			return nil, err
+		}
+		return signer.SignTx(ctx, tx, chainID)
+	})
+	if err != nil {
+		c.logger.Error("failed to sign token transaction", zap.Error(err), zap.String("token", token.Hex()), zap.String("sender_address", sender.Hex()), zap.String("receiver_address", receiver.Hex()), zap.String("amount", amount.String()))
+		return nil, err
+	}
+	return tx, nil
+}
+
+// callContract calls a view method of a contract and returns its single result.
+func callContract(ctx context.Context, ethClient *ethclient.Client, contractABI abi.ABI, contract common.Address, method string, args ...any) (any, error) {
+	data, err := contractABI.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s call: %w", method, err)
+	}
+
+	out, err := ethClient.CallContract(ctx, ethereum.CallMsg{To: &contract, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s: %w", method, err)
+	}
+	results, err := contractABI.Unpack(method, out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s result: %w", method, err)
+	}
+	if len(results) != 1 {
+		return nil, fmt.Errorf("unexpected %s result count %d", method, len(results))
+	}
+	return results[0], nil
+}
@@ -0,0 +1,21 @@
+package blockchain
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+func TestERC20TransferData(t *testing.T) {
+	receiver := common.HexToAddress("0xa512eb36e162bfb0e9f55b56bc2a070ca0d3ecd7")
+
+	data, err := erc20ABI.Pack("transfer", receiver, big.NewInt(5000))
+	require.NoError(t, err)
+	require.Equal(t,
+		"0xa9059cbb"+
+			"000000000000000000000000a512eb36e162bfb0e9f55b56bc2a070ca0d3ecd7"+
+			"0000000000000000000000000000000000000000000000000000000000001388",
+		hexutil.Encode(data))
+}
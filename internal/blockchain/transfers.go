@@ -0,0 +1,212 @@
+package blockchain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+// transferTopic is the topic of the ERC-20 Transfer(address,address,uint256) event.
+var transferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+// Transfer is a FIL or token transfer found on chain.
+type Transfer struct {
+	Hash        common.Hash
+	From        common.Address
+	To          common.Address
+	Amount      *big.Int
+	BlockNumber uint64
+	BlockHash   common.Hash
+	Time        time.Time
+	// Reverted is set for mined FIL transfers whose execution failed
+	Reverted bool
+	// LogIndex is the Transfer event of a token transfer, nil for FIL transfers
+	LogIndex *uint
+}
+
+// TransferFilter matches transfers sent by any of From or received by any of To.
+type TransferFilter struct {
+	From []common.Address
+	To   []common.Address
+}
+
+func (f TransferFilter) matches(from, to common.Address) bool {
+	return slices.Contains(f.From, from) || slices.Contains(f.To, to)
+}
+
+// rpcBlock is decoded by hand, Filecoin blocks and native messages do not pass
+// the header and signature checks of ethclient.BlockByNumber.
+type rpcBlock struct {
+	Hash         common.Hash      `json:"hash"`
+	Timestamp    hexutil.Uint64   `json:"timestamp"`
+	Transactions []rpcTransaction `json:"transactions"`
+}
+
+type rpcTransaction struct {
+	Hash  common.Hash     `json:"hash"`
+	From  common.Address  `json:"from"`
+	To    *common.Address `json:"to"`
+	Value *hexutil.Big    `json:"value"`
+}
+
+// FindFILTransfers returns the transactions of blocks from..to moving FIL that match filter.
+func (c *client) FindFILTransfers(ctx context.Context, filter TransferFilter, from, to uint64) ([]Transfer, error) {
+	ethClient, err := c.sdk.Extern().ConnectEthClient()
+	if err != nil {
+		return nil, err
+	}
+	defer ethClient.Close()
+
+	var transfers []Transfer
+	for number := from; number <= to; number++ {
+		block, err := getBlock(ctx, ethClient, number, true)
+		if err != nil {
+			return nil, err
+		}
+		// null rounds have no block
+		if block == nil {
+			continue
+		}
+
+		for _, tx := range block.Transactions {
+			if tx.To == nil || tx.Value == nil || tx.Value.ToInt().Sign() <= 0 {
+				continue
+			}
+			if !filter.matches(tx.From, *tx.To) {
+				continue
+			}
+
+			receipt, err := ethClient.TransactionReceipt(ctx, tx.Hash)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get tx receipt: %w", err)
+			}
+			transfers = append(transfers, Transfer{
+				Hash:        tx.Hash,
+				From:        tx.From,
+				To:          *tx.To,
+				Amount:      tx.Value.ToInt(),
+				BlockNumber: number,
+				BlockHash:   block.Hash,
+				Time:        time.Unix(int64(block.Timestamp), 0).UTC(),
+				Reverted:    receipt.Status != types.ReceiptStatusSuccessful,
+			})
+		}
+	}
+	return transfers, nil
+}
+
+// FindTokenTransfers returns the Transfer events of the ERC-20 token in blocks from..to
+// that match filter.
+func (c *client) FindTokenTransfers(ctx context.Context, token common.Address, filter TransferFilter, from, to uint64) ([]Transfer, error) {
+	ethClient, err := c.sdk.Extern().ConnectEthClient()
+	if err != nil {
+		return nil, err
+	}
+	defer ethClient.Close()
+
+	var queries [][][]common.Hash
+	if len(filter.From) > 0 {
+		queries = append(queries, [][]common.Hash{{transferTopic}, addressTopics(filter.From)})
+	}
+	if len(filter.To) > 0 {
+		queries = append(queries, [][]common.Hash{{transferTopic}, nil, addressTopics(filter.To)})
+	}
+
+	var logs []types.Log
+	for _, topics := range queries {
+		found, err := ethClient.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(from),
+			ToBlock:   new(big.Int).SetUint64(to),
+			Addresses: []common.Address{token},
+			Topics:    topics,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get transfer logs: %w", err)
+		}
+		logs = append(logs, found...)
+	}
+
+	type logID struct {
+		tx    common.Hash
+		index uint
+	}
+	seen := make(map[logID]bool)
+	times := make(map[uint64]time.Time)
+	var transfers []Transfer
+	for _, log := range logs {
+		// transfers between filtered addresses match both queries
+		id := logID{log.TxHash, log.Index}
+		if seen[id] || log.Removed || len(log.Topics) != 3 {
+			continue
+		}
+		seen[id] = true
+
+		blockTime, ok := times[log.BlockNumber]
+		if !ok {
+			block, err := getBlock(ctx, ethClient, log.BlockNumber, false)
+			if err != nil {
+				return nil, err
+			}
+			if block == nil {
+				return nil, fmt.Errorf("block %d of transfer log not found", log.BlockNumber)
+			}
+			blockTime = time.Unix(int64(block.Timestamp), 0).UTC()
+			times[log.BlockNumber] = blockTime
+		}
+
+		logIndex := log.Index
+		transfers = append(transfers, Transfer{
+			Hash:        log.TxHash,
+			From:        common.BytesToAddress(log.Topics[1].Bytes()),
+			To:          common.BytesToAddress(log.Topics[2].Bytes()),
+			Amount:      new(big.Int).SetBytes(log.Data),
+			BlockNumber: log.BlockNumber,
+			BlockHash:   log.BlockHash,
+			Time:        blockTime,
+			LogIndex:    &logIndex,
+		})
+	}
+	return transfers, nil
+}
+
+func addressTopics(addresses []common.Address) []common.Hash {
+	topics := make([]common.Hash, 0, len(addresses))
+	for _, address := range addresses {
+		topics = append(topics, common.BytesToHash(address.Bytes()))
+	}
+	return topics
+}
+
+// getBlock returns nil for null rounds.
+func getBlock(ctx context.Context, ethClient *ethclient.Client, number uint64, withTxs bool) (*rpcBlock, error) {
+	var raw json.RawMessage
+	if err := ethClient.Client().CallContext(ctx, &raw, "eth_getBlockByNumber", hexutil.EncodeUint64(number), withTxs); err != nil {
+		if isNullRound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get block %d: %w", number, err)
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	var block rpcBlock
+	if err := json.Unmarshal(raw, &block); err != nil {
+		return nil, fmt.Errorf("failed to decode block %d: %w", number, err)
+	}
+	return &block, nil
+}
+
+func isNullRound(err error) bool {
+	return strings.Contains(err.Error(), "null round")
+}
@@ -0,0 +1,52 @@
+package cron
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestSchedule_Next(t *testing.T) {
+	from := time.Date(2025, time.April, 13, 13, 4, 46, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2025, time.April, 13, 13, 5, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2025, time.April, 13, 13, 15, 0, 0, time.UTC)},
+		{"30 9 * * 1-5", time.Date(2025, time.April, 14, 9, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 29 2 *", time.Date(2028, time.February, 29, 12, 0, 0, 0, time.UTC)},
+		{"0 8 1,15 * *", time.Date(2025, time.April, 15, 8, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2025, time.April, 20, 0, 0, 0, 0, time.UTC)},
+		// day of month and day of week are OR-ed when both are restricted
+		{"0 0 20 * 1", time.Date(2025, time.April, 14, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := Parse(tt.expr)
+			require.NoError(t, err)
+
+			next, err := s.Next(from)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, next)
+		})
+	}
+}
+
+func TestSchedule_NeverMatches(t *testing.T) {
+	s, err := Parse("0 0 30 2 *")
+	require.NoError(t, err)
+
+	_, err = s.Next(time.Now())
+	require.ErrorIs(t, err, ErrNoNextRun)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "5-1 * * * *", "*/0 * * * *", "a * * * *"} {
+		_, err := Parse(expr)
+		require.Error(t, err, expr)
+	}
+}
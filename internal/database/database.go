@@ -8,17 +8,34 @@ import (
 	"github.com/golang-migrate/migrate/v4"
 	migratepg "github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 	"path/filepath"
 	"runtime"
+	"slices"
+	"strings"
+	"time"
 )
 
 const limit = 100
 
-var ErrTxExists = errors.New("transaction already exists and it is not pending")
+var (
+	ErrTxExists         = errors.New("transaction already exists and it is not pending")
+	ErrTxNotFound       = errors.New("transaction not found")
+	ErrBatchNotFound    = errors.New("batch not found")
+	ErrScheduleNotFound = errors.New("schedule not found")
+	ErrNoCheckpoint     = errors.New("no import checkpoint")
+	ErrNoCursor         = errors.New("no block cursor")
+
+	ErrWatchedAddressExists   = errors.New("address is already watched")
+	ErrWatchedAddressNotFound = errors.New("watched address not found")
+	ErrTokenExists            = errors.New("token is already registered")
+	ErrTokenNotFound          = errors.New("token not found")
+)
 
 type driver struct {
 	logger *zap.Logger
@@ -27,7 +44,48 @@ type driver struct {
 
 type Database interface {
 	SaveTransaction(tx *models.Transaction) error
-	GetTransactions(ctx context.Context, sender, receiver string, offset int) ([]models.Transaction, error)
+	GetTransactions(ctx context.Context, filter TransactionFilter) ([]models.Transaction, error)
+	GetPendingTransactions(ctx context.Context) ([]models.Transaction, error)
+	GetQueuedTransactions(ctx context.Context) ([]models.Transaction, error)
+	ClaimQueuedTransaction(ctx context.Context, hash string) error
+	MaxQueuedNonce(ctx context.Context, sender string) (*uint64, error)
+	GetConfirmedTransactions(ctx context.Context) ([]models.Transaction, error)
+	RecordBroadcastFailure(ctx context.Context, hash, reason string) error
+	UpdateTransactionStatus(ctx context.Context, hash string, status models.TransactionStatus) error
+	UpdateTransactionReceipt(ctx context.Context, hash string, status models.TransactionStatus, receipt models.Receipt) error
+	FinalizeTransaction(ctx context.Context, hash string, at time.Time) error
+	GetTransaction(ctx context.Context, hash string) (*models.Transaction, error)
+	MarkSuperseded(ctx context.Context, original, winner string, status models.TransactionStatus) error
+	MarkSeen(ctx context.Context, hash string, at time.Time) error
+	MarkDropped(ctx context.Context, hash, reason string) error
+	CancelQueuedTransaction(ctx context.Context, hash string) error
+	GetOpenApproval(ctx context.Context, sender string, amount decimal.Decimal) (*models.Transaction, error)
+	RevertToPending(ctx context.Context, hash string, at time.Time) error
+	CreateBatch(ctx context.Context, batch *models.Batch) error
+	GetBatch(ctx context.Context, id uint64) (*models.Batch, error)
+	GetBatchTransactions(ctx context.Context, id uint64) ([]models.Transaction, error)
+	CreateSchedule(ctx context.Context, schedule *models.Schedule) error
+	GetSchedule(ctx context.Context, id uint64) (*models.Schedule, error)
+	GetSchedules(ctx context.Context) ([]models.Schedule, error)
+	UpdateSchedule(ctx context.Context, schedule *models.Schedule) error
+	GetDueSchedules(ctx context.Context, now time.Time) ([]models.Schedule, error)
+	ClaimScheduleRun(ctx context.Context, id uint64, runAt time.Time, next *time.Time) (bool, error)
+	RecordScheduleRun(ctx context.Context, id uint64, txHash, runErr *string) error
+	GetImportCheckpoint(ctx context.Context, address string) (*models.ImportCheckpoint, error)
+	ImportTransactions(ctx context.Context, address string, txs []models.Transaction, lastBlock uint64) (int64, error)
+	CreateWatchedAddress(ctx context.Context, watched *models.WatchedAddress) error
+	GetWatchedAddresses(ctx context.Context) ([]models.WatchedAddress, error)
+	GetWatchedAddress(ctx context.Context, address string) (*models.WatchedAddress, error)
+	UpdateWatchedAddress(ctx context.Context, watched *models.WatchedAddress) error
+	DeleteWatchedAddress(ctx context.Context, address string) error
+	GetBlockCursor(ctx context.Context, name string) (*models.BlockCursor, error)
+	RecordIncomingTransactions(ctx context.Context, cursor string, txs []models.Transaction, lastBlock uint64) (int64, error)
+	CreateRegisteredToken(ctx context.Context, token *models.RegisteredToken) error
+	GetRegisteredTokens(ctx context.Context) ([]models.RegisteredToken, error)
+	GetRegisteredToken(ctx context.Context, symbol string) (*models.RegisteredToken, error)
+	DeleteRegisteredToken(ctx context.Context, symbol string) error
+	ReserveIdempotencyKey(ctx context.Context, key, fingerprint string, staleBefore time.Time) (*models.IdempotencyKey, bool, error)
+	ReleaseIdempotencyKey(ctx context.Context, key string) error
 }
 
 func NewDriver(logger *zap.Logger, dsn string) (Database, error) {
@@ -63,13 +121,18 @@ func NewDriver(logger *zap.Logger, dsn string) (Database, error) {
 	return &driver{logger: logger, db: db}, nil
 }
 
+// SaveTransaction stores tx. A pending transaction may be saved again with another
+// status, but not queued once more, it was broadcast already. The idempotency key
+// of tx is completed in the same transaction.
 func (d *driver) SaveTransaction(tx *models.Transaction) error {
 	return d.db.Transaction(func(dbTx *gorm.DB) error {
 		result := dbTx.Clauses(clause.OnConflict{
-			Columns:   []clause.Column{{Name: "hash"}},
-			DoUpdates: clause.AssignmentColumns([]string{"status"}),
+			Columns:     []clause.Column{{Name: "hash"}},
+			TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "log_index IS NULL"}}},
+			DoUpdates:   clause.AssignmentColumns([]string{"status"}),
 			Where: clause.Where{Exprs: []clause.Expression{
 				clause.Expr{SQL: `"transactions"."status" = ?`, Vars: []interface{}{"pending"}},
+				clause.Expr{SQL: `excluded.status <> ?`, Vars: []interface{}{"queued"}},
 			}},
 		}).Omit("id").Create(tx)
 
@@ -80,24 +143,700 @@ func (d *driver) SaveTransaction(tx *models.Transaction) error {
 		if result.RowsAffected == 0 {
 			return ErrTxExists
 		}
+
+		if tx.IdempotencyKey != nil {
+			return dbTx.Model(&models.IdempotencyKey{}).
+				Where("key = ?", *tx.IdempotencyKey).
+				Update("tx_hash", tx.Hash).Error
+		}
 		return nil
 	})
 }
 
-func (d *driver) GetTransactions(ctx context.Context, sender, receiver string, offset int) ([]models.Transaction, error) {
+// TransactionFilter selects transactions, empty fields match everything.
+type TransactionFilter struct {
+	Sender    string
+	Receiver  string
+	Label     string
+	Reference string
+	Direction models.TransactionDirection
+	Offset    int
+}
+
+func (d *driver) GetTransactions(ctx context.Context, filter TransactionFilter) ([]models.Transaction, error) {
 	var transactions []models.Transaction
 
 	db := d.db.WithContext(ctx)
-	switch {
-	case sender != "" && receiver != "":
-		db = db.Where("sender = ? AND receiver = ?", sender, receiver)
-	case sender != "":
-		db = db.Where("sender = ?", sender)
-	case receiver != "":
-		db = db.Where("receiver = ?", receiver)
+	if filter.Sender != "" {
+		db = db.Where("sender = ?", filter.Sender)
+	}
+	if filter.Receiver != "" {
+		db = db.Where("receiver = ?", filter.Receiver)
+	}
+	if filter.Label != "" {
+		db = db.Where("labels @> ?", pq.StringArray{filter.Label})
+	}
+	if filter.Reference != "" {
+		db = db.Where("reference = ?", filter.Reference)
+	}
+	if filter.Direction != "" {
+		db = db.Where("direction = ?", filter.Direction)
+	}
+	if err := db.Limit(limit).Offset(filter.Offset).Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+func (d *driver) GetPendingTransactions(ctx context.Context) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+
+	err := d.db.WithContext(ctx).
+		Where("status = ?", models.StatusPending).
+		Order("id").
+		Find(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// GetConfirmedTransactions returns mined transactions which are not final yet. Besides
+// confirmed ones these are the reverted ones, which a reorg can undo as well.
+func (d *driver) GetConfirmedTransactions(ctx context.Context) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+
+	err := d.db.WithContext(ctx).
+		Where("status = ? OR (status = ? AND block_hash IS NOT NULL AND finalized_at IS NULL)",
+			models.StatusConfirmed, models.StatusFailed).
+		Order("id").
+		Find(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// GetQueuedTransactions returns transactions which are not broadcast yet, in nonce order per sender.
+func (d *driver) GetQueuedTransactions(ctx context.Context) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+
+	err := d.db.WithContext(ctx).
+		Where("status IN ?", []models.TransactionStatus{models.StatusQueued, models.StatusBroadcasting}).
+		Order("sender, nonce, id").
+		Find(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// ClaimQueuedTransaction moves a queued transaction to broadcasting, so it can no
+// longer be cancelled while it is sent. A transaction left broadcasting by a restart
+// is claimed again. It fails with ErrTxNotFound if the transaction is not queued.
+func (d *driver) ClaimQueuedTransaction(ctx context.Context, hash string) error {
+	result := d.db.WithContext(ctx).
+		Model(&models.Transaction{}).
+		Where("hash = ? AND status IN ?", hash, []models.TransactionStatus{models.StatusQueued, models.StatusBroadcasting}).
+		Update("status", models.StatusBroadcasting)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrTxNotFound
+	}
+	return nil
+}
+
+// MaxQueuedNonce returns the highest nonce of the queued transactions of sender, nil if there is none.
+func (d *driver) MaxQueuedNonce(ctx context.Context, sender string) (*uint64, error) {
+	var nonce *uint64
+
+	err := d.db.WithContext(ctx).
+		Model(&models.Transaction{}).
+		Where("sender = ? AND status IN ?", sender, []models.TransactionStatus{models.StatusQueued, models.StatusBroadcasting}).
+		Select("MAX(nonce)").
+		Scan(&nonce).Error
+	if err != nil {
+		return nil, err
+	}
+	return nonce, nil
+}
+
+func (d *driver) RecordBroadcastFailure(ctx context.Context, hash, reason string) error {
+	return d.db.WithContext(ctx).
+		Model(&models.Transaction{}).
+		Where("hash = ?", hash).
+		Updates(map[string]interface{}{
+			"broadcast_attempts": gorm.Expr("broadcast_attempts + 1"),
+			"last_error":         reason,
+		}).Error
+}
+
+func (d *driver) UpdateTransactionStatus(ctx context.Context, hash string, status models.TransactionStatus) error {
+	result := d.db.WithContext(ctx).
+		Model(&models.Transaction{}).
+		Where("hash = ?", hash).
+		Update("status", status)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrTxNotFound
+	}
+	return nil
+}
+
+// UpdateTransactionReceipt sets the final status of a mined transaction together with its receipt fields.
+func (d *driver) UpdateTransactionReceipt(ctx context.Context, hash string, status models.TransactionStatus, receipt models.Receipt) error {
+	result := d.db.WithContext(ctx).
+		Model(&models.Transaction{}).
+		Where("hash = ?", hash).
+		Updates(map[string]interface{}{
+			"status":              status,
+			"block_number":        receipt.BlockNumber,
+			"block_hash":          receipt.BlockHash,
+			"gas_used":            receipt.GasUsed,
+			"effective_gas_price": receipt.EffectiveGasPrice,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrTxNotFound
+	}
+	return nil
+}
+
+// FinalizeTransaction records that the block of a mined transaction is deep enough.
+// Confirmed transactions move to finalized, reverted ones keep their status.
+func (d *driver) FinalizeTransaction(ctx context.Context, hash string, at time.Time) error {
+	result := d.db.WithContext(ctx).
+		Model(&models.Transaction{}).
+		Where("hash = ?", hash).
+		Updates(map[string]interface{}{
+			"status":       gorm.Expr("CASE WHEN status = ? THEN ?::tx_status ELSE status END", models.StatusConfirmed, models.StatusFinalized),
+			"finalized_at": at,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrTxNotFound
+	}
+	return nil
+}
+
+func (d *driver) GetTransaction(ctx context.Context, hash string) (*models.Transaction, error) {
+	var tx models.Transaction
+
+	err := d.db.WithContext(ctx).Where("hash = ?", hash).First(&tx).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTxNotFound
+		}
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// MarkSuperseded moves every pending transaction sharing the nonce of original,
+// i.e. original itself and all of its replacements, except winner to status.
+// Members already dropped because the winner used their nonce are moved as well.
+func (d *driver) MarkSuperseded(ctx context.Context, original, winner string, status models.TransactionStatus) error {
+	return d.db.WithContext(ctx).
+		Model(&models.Transaction{}).
+		Where("(hash = ? OR replaces = ?) AND hash <> ? AND status IN ?", original, original, winner,
+			[]models.TransactionStatus{models.StatusPending, models.StatusDropped}).
+		Updates(map[string]interface{}{
+			"status":      status,
+			"drop_reason": nil,
+		}).Error
+}
+
+func (d *driver) MarkSeen(ctx context.Context, hash string, at time.Time) error {
+	return d.db.WithContext(ctx).
+		Model(&models.Transaction{}).
+		Where("hash = ?", hash).
+		Update("last_seen_at", at).Error
+}
+
+// MarkDropped moves a pending transaction to dropped. Transactions which left
+// pending meanwhile are not touched.
+func (d *driver) MarkDropped(ctx context.Context, hash, reason string) error {
+	return d.db.WithContext(ctx).
+		Model(&models.Transaction{}).
+		Where("hash = ? AND status = ?", hash, models.StatusPending).
+		Updates(map[string]interface{}{
+			"status":      models.StatusDropped,
+			"drop_reason": reason,
+		}).Error
+}
+
+// CancelQueuedTransaction moves a transaction which was never broadcast to cancelled.
+// It fails with ErrTxNotFound if the transaction is not queued (anymore).
+func (d *driver) CancelQueuedTransaction(ctx context.Context, hash string) error {
+	result := d.db.WithContext(ctx).
+		Model(&models.Transaction{}).
+		Where("hash = ? AND status = ?", hash, models.StatusQueued).
+		Update("status", models.StatusCancelled)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrTxNotFound
+	}
+	return nil
+}
+
+// GetOpenApproval returns the latest approval of sender for at least amount which
+// is not mined yet. It fails with ErrTxNotFound if there is none.
+func (d *driver) GetOpenApproval(ctx context.Context, sender string, amount decimal.Decimal) (*models.Transaction, error) {
+	var tx models.Transaction
+
+	err := d.db.WithContext(ctx).
+		Where("sender = ? AND kind = ? AND status IN ? AND amount >= ?", sender, models.KindApproval,
+			[]models.TransactionStatus{models.StatusQueued, models.StatusBroadcasting, models.StatusPending}, amount).
+		Order("id DESC").
+		First(&tx).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTxNotFound
+		}
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// RevertToPending moves a confirmed transaction whose block was reorganised away back
+// to pending and clears its receipt fields. The transactions it superseded are
+// pending again as well, since any of them may be mined now.
+func (d *driver) RevertToPending(ctx context.Context, hash string, at time.Time) error {
+	return d.db.WithContext(ctx).Transaction(func(dbTx *gorm.DB) error {
+		var tx models.Transaction
+		if err := dbTx.Where("hash = ?", hash).First(&tx).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrTxNotFound
+			}
+			return err
+		}
+
+		err := dbTx.Model(&models.Transaction{}).
+			Where("hash = ?", hash).
+			Updates(map[string]interface{}{
+				"status":              models.StatusPending,
+				"block_number":        nil,
+				"block_hash":          nil,
+				"gas_used":            nil,
+				"effective_gas_price": nil,
+				"last_seen_at":        at,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		root := hash
+		if tx.Replaces != nil {
+			root = *tx.Replaces
+		}
+		return dbTx.Model(&models.Transaction{}).
+			Where("(hash = ? OR replaces = ?) AND hash <> ? AND status IN ?", root, root, hash,
+				[]models.TransactionStatus{models.StatusReplaced, models.StatusCancelled}).
+			Updates(map[string]interface{}{
+				"status":       models.StatusPending,
+				"last_seen_at": at,
+			}).Error
+	})
+}
+
+func (d *driver) GetImportCheckpoint(ctx context.Context, address string) (*models.ImportCheckpoint, error) {
+	var checkpoint models.ImportCheckpoint
+
+	err := d.db.WithContext(ctx).Where("address = ?", address).First(&checkpoint).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNoCheckpoint
+		}
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+// ImportTransactions stores transactions found on chain for address and moves its
+// checkpoint to lastBlock, if that is higher. Transactions already stored are skipped.
+// It returns the number of stored transactions.
+func (d *driver) ImportTransactions(ctx context.Context, address string, txs []models.Transaction, lastBlock uint64) (int64, error) {
+	var imported int64
+	err := d.db.WithContext(ctx).Transaction(func(dbTx *gorm.DB) error {
+		var err error
+		if imported, err = insertExternalTransactions(dbTx, txs); err != nil {
+			return err
+		}
+
+		return dbTx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "address"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"last_block": gorm.Expr("GREATEST(import_checkpoints.last_block, EXCLUDED.last_block)"),
+				"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+			}),
+		}).Create(&models.ImportCheckpoint{Address: address, LastBlock: lastBlock}).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return imported, nil
+}
+
+// insertExternalTransactions stores transactions found on chain, skipping the ones already
+// stored. Token transfers are told apart by their log index, so every Transfer event of a
+// transaction is stored, except the one a transaction submitted through this service is
+// stored for already. Other events of such a transaction, e.g. the iFIL minted by a pool
+// deposit, are stored.
+func insertExternalTransactions(dbTx *gorm.DB, txs []models.Transaction) (int64, error) {
+	if len(txs) == 0 {
+		return 0, nil
+	}
+
+	hashes := make([]string, 0, len(txs))
+	for _, tx := range txs {
+		hashes = append(hashes, tx.Hash)
+	}
+	var submitted []models.Transaction
+	err := dbTx.Select("hash", "sender", "token").
+		Where("hash IN ? AND source = ?", hashes, models.SourceService).
+		Find(&submitted).Error
+	if err != nil {
+		return 0, err
+	}
+
+	external := make([]models.Transaction, 0, len(txs))
+	for _, tx := range txs {
+		stored := slices.ContainsFunc(submitted, func(s models.Transaction) bool {
+			return s.Hash == tx.Hash && s.Token == tx.Token && strings.EqualFold(s.Sender, tx.Sender)
+		})
+		if !stored {
+			external = append(external, tx)
+		}
+	}
+	if len(external) == 0 {
+		return 0, nil
 	}
-	if err := db.Find(&transactions).Limit(limit).Offset(offset).Error; err != nil {
+
+	// no conflict target, a row may collide with either unique index on hash
+	result := dbTx.Clauses(clause.OnConflict{DoNothing: true}).Omit("id").Create(&external)
+	return result.RowsAffected, result.Error
+}
+
+func (d *driver) CreateWatchedAddress(ctx context.Context, watched *models.WatchedAddress) error {
+	result := d.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(watched)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrWatchedAddressExists
+	}
+	return nil
+}
+
+func (d *driver) GetWatchedAddresses(ctx context.Context) ([]models.WatchedAddress, error) {
+	var watched []models.WatchedAddress
+
+	if err := d.db.WithContext(ctx).Order("created_at").Find(&watched).Error; err != nil {
+		return nil, err
+	}
+	return watched, nil
+}
+
+func (d *driver) GetWatchedAddress(ctx context.Context, address string) (*models.WatchedAddress, error) {
+	var watched models.WatchedAddress
+
+	err := d.db.WithContext(ctx).Where("address = ?", address).First(&watched).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWatchedAddressNotFound
+		}
+		return nil, err
+	}
+	return &watched, nil
+}
+
+func (d *driver) UpdateWatchedAddress(ctx context.Context, watched *models.WatchedAddress) error {
+	result := d.db.WithContext(ctx).
+		Model(&models.WatchedAddress{}).
+		Where("address = ?", watched.Address).
+		Update("label", watched.Label)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrWatchedAddressNotFound
+	}
+	return nil
+}
+
+func (d *driver) DeleteWatchedAddress(ctx context.Context, address string) error {
+	result := d.db.WithContext(ctx).Where("address = ?", address).Delete(&models.WatchedAddress{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrWatchedAddressNotFound
+	}
+	return nil
+}
+
+func (d *driver) GetBlockCursor(ctx context.Context, name string) (*models.BlockCursor, error) {
+	var cursor models.BlockCursor
+
+	err := d.db.WithContext(ctx).Where("name = ?", name).First(&cursor).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNoCursor
+		}
+		return nil, err
+	}
+	return &cursor, nil
+}
+
+// RecordIncomingTransactions stores transfers found by a block follower and moves
+// its cursor to lastBlock. Transactions already stored are skipped.
+func (d *driver) RecordIncomingTransactions(ctx context.Context, cursor string, txs []models.Transaction, lastBlock uint64) (int64, error) {
+	var recorded int64
+	err := d.db.WithContext(ctx).Transaction(func(dbTx *gorm.DB) error {
+		var err error
+		if recorded, err = insertExternalTransactions(dbTx, txs); err != nil {
+			return err
+		}
+
+		return dbTx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "name"}},
+			DoUpdates: clause.AssignmentColumns([]string{"last_block", "updated_at"}),
+		}).Create(&models.BlockCursor{Name: cursor, LastBlock: lastBlock, UpdatedAt: time.Now()}).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return recorded, nil
+}
+
+// CreateRegisteredToken fails with ErrTokenExists if the address or the symbol,
+// compared case-insensitively, is registered already.
+func (d *driver) CreateRegisteredToken(ctx context.Context, token *models.RegisteredToken) error {
+	result := d.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(token)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrTokenExists
+	}
+	return nil
+}
+
+func (d *driver) GetRegisteredTokens(ctx context.Context) ([]models.RegisteredToken, error) {
+	var tokens []models.RegisteredToken
+
+	if err := d.db.WithContext(ctx).Order("symbol").Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (d *driver) GetRegisteredToken(ctx context.Context, symbol string) (*models.RegisteredToken, error) {
+	var token models.RegisteredToken
+
+	err := d.db.WithContext(ctx).Where("LOWER(symbol) = LOWER(?)", symbol).First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTokenNotFound
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (d *driver) DeleteRegisteredToken(ctx context.Context, symbol string) error {
+	result := d.db.WithContext(ctx).Where("LOWER(symbol) = LOWER(?)", symbol).Delete(&models.RegisteredToken{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrTokenNotFound
+	}
+	return nil
+}
+
+// ReserveIdempotencyKey stores key if it is unused and reports whether it did.
+// A key still in progress since before staleBefore is taken over by a request with
+// the same fingerprint, its submission is assumed to have died. A key is never taken
+// over once a transaction was queued for it. Otherwise the existing record is returned.
+func (d *driver) ReserveIdempotencyKey(ctx context.Context, key, fingerprint string, staleBefore time.Time) (*models.IdempotencyKey, bool, error) {
+	record := &models.IdempotencyKey{Key: key, Fingerprint: fingerprint, CreatedAt: time.Now()}
+	result := d.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(record)
+	if result.Error != nil {
+		return nil, false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return record, true, nil
+	}
+
+	result = d.db.WithContext(ctx).
+		Model(&models.IdempotencyKey{}).
+		Where("key = ? AND fingerprint = ? AND tx_hash IS NULL AND created_at < ?", key, fingerprint, staleBefore).
+		Where("NOT EXISTS (SELECT 1 FROM transactions WHERE idempotency_key = ?)", key).
+		Update("created_at", record.CreatedAt)
+	if result.Error != nil {
+		return nil, false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return record, true, nil
+	}
+
+	var existing models.IdempotencyKey
+	if err := d.db.WithContext(ctx).Where("key = ?", key).First(&existing).Error; err != nil {
+		return nil, false, err
+	}
+	return &existing, false, nil
+}
+
+// ReleaseIdempotencyKey drops an in-progress key, so the request can be retried.
+func (d *driver) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	return d.db.WithContext(ctx).
+		Where("key = ? AND tx_hash IS NULL", key).
+		Delete(&models.IdempotencyKey{}).Error
+}
+
+func (d *driver) CreateBatch(ctx context.Context, batch *models.Batch) error {
+	return d.db.WithContext(ctx).Create(batch).Error
+}
+
+func (d *driver) GetBatch(ctx context.Context, id uint64) (*models.Batch, error) {
+	var batch models.Batch
+
+	err := d.db.WithContext(ctx).Where("id = ?", id).First(&batch).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBatchNotFound
+		}
+		return nil, err
+	}
+	return &batch, nil
+}
+
+func (d *driver) GetBatchTransactions(ctx context.Context, id uint64) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+
+	err := d.db.WithContext(ctx).
+		Where("batch_id = ?", id).
+		Order("nonce, id").
+		Find(&transactions).Error
+	if err != nil {
 		return nil, err
 	}
 	return transactions, nil
 }
+
+func (d *driver) CreateSchedule(ctx context.Context, schedule *models.Schedule) error {
+	return d.db.WithContext(ctx).Create(schedule).Error
+}
+
+func (d *driver) GetSchedule(ctx context.Context, id uint64) (*models.Schedule, error) {
+	var schedule models.Schedule
+
+	err := d.db.WithContext(ctx).Where("id = ?", id).First(&schedule).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrScheduleNotFound
+		}
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (d *driver) GetSchedules(ctx context.Context) ([]models.Schedule, error) {
+	var schedules []models.Schedule
+
+	err := d.db.WithContext(ctx).
+		Where("status <> ?", models.ScheduleCancelled).
+		Order("id").
+		Limit(limit).
+		Find(&schedules).Error
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (d *driver) UpdateSchedule(ctx context.Context, schedule *models.Schedule) error {
+	result := d.db.WithContext(ctx).
+		Model(schedule).
+		Select("receiver", "amount", "token", "token_address", "speed", "cron", "next_run_at", "status").
+		Updates(schedule)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrScheduleNotFound
+	}
+	return nil
+}
+
+func (d *driver) GetDueSchedules(ctx context.Context, now time.Time) ([]models.Schedule, error) {
+	var schedules []models.Schedule
+
+	err := d.db.WithContext(ctx).
+		Where("status = ? AND next_run_at <= ?", models.ScheduleActive, now).
+		Order("next_run_at").
+		Find(&schedules).Error
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// ClaimScheduleRun moves the schedule from its run at runAt to next, or completes
+// it if next is nil. It reports false if the run was claimed or changed meanwhile.
+func (d *driver) ClaimScheduleRun(ctx context.Context, id uint64, runAt time.Time, next *time.Time) (bool, error) {
+	updates := map[string]interface{}{
+		"next_run_at": next,
+		"last_run_at": time.Now(),
+	}
+	if next == nil {
+		updates["status"] = models.ScheduleCompleted
+	}
+
+	result := d.db.WithContext(ctx).
+		Model(&models.Schedule{}).
+		Where("id = ? AND status = ? AND next_run_at = ?", id, models.ScheduleActive, runAt).
+		Updates(updates)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (d *driver) RecordScheduleRun(ctx context.Context, id uint64, txHash, runErr *string) error {
+	return d.db.WithContext(ctx).
+		Model(&models.Schedule{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"last_tx_hash": txHash,
+			"last_error":   runErr,
+		}).Error
+}
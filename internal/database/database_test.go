@@ -59,7 +59,7 @@ func TestDriver_WithDockerCompose(t *testing.T) {
 	require.True(t, exists, "transactions table should exist after migration")
 
 	// get any tx from db
-	txs, err := driver.GetTransactions(ctx, "", "", 0)
+	txs, err := driver.GetTransactions(ctx, TransactionFilter{})
 	require.NoError(t, err)
 	require.Len(t, txs, 0)
 
@@ -81,7 +81,7 @@ func TestDriver_WithDockerCompose(t *testing.T) {
 	err = driver.SaveTransaction(tx)
 	require.NoError(t, err)
 
-	txs, err = driver.GetTransactions(ctx, sender, "", 0)
+	txs, err = driver.GetTransactions(ctx, TransactionFilter{Sender: sender})
 	require.NoError(t, err)
 	require.Len(t, txs, 1)
 	actualTx := txs[0]
@@ -92,13 +92,23 @@ func TestDriver_WithDockerCompose(t *testing.T) {
 	require.True(t, !tx.Timestamp.IsZero())
 	require.Equal(t, tx.Amount.String(), actualTx.Amount.String())
 	require.Equal(t, tx.Status, actualTx.Status)
+	require.Equal(t, models.TokenFIL, actualTx.Token)
+
+	// submitting the same tx again does not queue it once more
+	tx.Status = models.StatusQueued
+	err = driver.SaveTransaction(tx)
+	require.ErrorIs(t, err, ErrTxExists)
+
+	resubmitted, err := driver.GetTransaction(ctx, txHash)
+	require.NoError(t, err)
+	require.Equal(t, models.StatusPending, resubmitted.Status)
 
 	// save same tx, but with updated status
 	tx.Status = models.StatusFailed
 	err = driver.SaveTransaction(tx)
 	require.NoError(t, err)
 
-	txs, err = driver.GetTransactions(ctx, sender, "", 0)
+	txs, err = driver.GetTransactions(ctx, TransactionFilter{Sender: sender})
 	require.NoError(t, err)
 	require.Len(t, txs, 1)
 
@@ -113,4 +123,212 @@ func TestDriver_WithDockerCompose(t *testing.T) {
 	tx.Status = models.StatusConfirmed
 	err = driver.SaveTransaction(tx)
 	require.ErrorIs(t, err, ErrTxExists)
+
+	// a failed transaction is verified until final only if it was mined
+	confirmed, err := driver.GetConfirmedTransactions(ctx)
+	require.NoError(t, err)
+	require.Len(t, confirmed, 0)
+
+	const blockHash = "0x1a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f809"
+	require.NoError(t, driver.UpdateTransactionReceipt(ctx, txHash, models.StatusFailed, models.Receipt{BlockNumber: 100, BlockHash: blockHash}))
+	confirmed, err = driver.GetConfirmedTransactions(ctx)
+	require.NoError(t, err)
+	require.Len(t, confirmed, 1)
+
+	require.NoError(t, driver.FinalizeTransaction(ctx, txHash, time.Now()))
+	confirmed, err = driver.GetConfirmedTransactions(ctx)
+	require.NoError(t, err)
+	require.Len(t, confirmed, 0)
+	finalized, err := driver.GetTransaction(ctx, txHash)
+	require.NoError(t, err)
+	require.Equal(t, models.StatusFailed, finalized.Status)
+	require.NotNil(t, finalized.FinalizedAt)
+
+	// filter by label and reference
+	const labeledHash = "0x15e54e2f6e60be523a4ef44e3dc3ab7245bdc98d8b007bfcf1628a320983384b"
+	reference := "INV-2025-0042"
+	err = driver.SaveTransaction(&models.Transaction{
+		Hash:      labeledHash,
+		Sender:    sender,
+		Receiver:  receiver,
+		Amount:    decimal.NewFromInt(1),
+		Status:    models.StatusPending,
+		Labels:    []string{"payroll", "april"},
+		Reference: &reference,
+	})
+	require.NoError(t, err)
+
+	txs, err = driver.GetTransactions(ctx, TransactionFilter{Label: "payroll"})
+	require.NoError(t, err)
+	require.Len(t, txs, 1)
+	require.Equal(t, labeledHash, txs[0].Hash)
+	require.ElementsMatch(t, []string{"payroll", "april"}, txs[0].Labels)
+
+	txs, err = driver.GetTransactions(ctx, TransactionFilter{Sender: sender, Reference: reference})
+	require.NoError(t, err)
+	require.Len(t, txs, 1)
+	require.Equal(t, labeledHash, txs[0].Hash)
+
+	txs, err = driver.GetTransactions(ctx, TransactionFilter{Label: "unknown"})
+	require.NoError(t, err)
+	require.Len(t, txs, 0)
+
+	// watch an address and record an incoming transfer
+	require.NoError(t, driver.CreateWatchedAddress(ctx, &models.WatchedAddress{Address: receiver}))
+	require.ErrorIs(t, driver.CreateWatchedAddress(ctx, &models.WatchedAddress{Address: receiver}), ErrWatchedAddressExists)
+
+	_, err = driver.GetBlockCursor(ctx, "incoming")
+	require.ErrorIs(t, err, ErrNoCursor)
+
+	const incomingHash = "0x7f2b1e7d3c6c2f7b0c1b7a8a4f8e6d5c4b3a29181716151413121110f0e0d0c0"
+	incoming := models.Transaction{
+		Hash:      incomingHash,
+		Sender:    sender,
+		Receiver:  receiver,
+		Amount:    decimal.NewFromInt(1),
+		Status:    models.StatusConfirmed,
+		Source:    models.SourceExternal,
+		Direction: models.DirectionIncoming,
+	}
+	recorded, err := driver.RecordIncomingTransactions(ctx, "incoming", []models.Transaction{incoming}, 120)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), recorded)
+	// recording the same block range again skips stored transactions
+	recorded, err = driver.RecordIncomingTransactions(ctx, "incoming", []models.Transaction{incoming}, 120)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), recorded)
+
+	// token transfers of one transaction are told apart by their log index
+	const incomingTokenHash = "0x9a8b7c6d5e4f30211a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f7081"
+	firstLog, secondLog := uint(1), uint(4)
+	tokenEvents := []models.Transaction{incoming, incoming}
+	for i, logIndex := range []*uint{&firstLog, &secondLog} {
+		tokenEvents[i].Hash = incomingTokenHash
+		tokenEvents[i].Token = models.TokenIFIL
+		tokenEvents[i].LogIndex = logIndex
+	}
+	recorded, err = driver.RecordIncomingTransactions(ctx, "incoming", tokenEvents, 120)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), recorded)
+
+	// every Transfer event of a transaction is imported, once
+	const multiHash = "0x2c8e4f0a6b1d3e5f7a9c0b2d4e6f8a1c3e5a7c9e0b2d4f6a8c1e3a5c7e9b0d2f"
+	first, second := uint(3), uint(7)
+	events := []models.Transaction{
+		{Hash: multiHash, Sender: receiver, Receiver: sender, Amount: decimal.NewFromInt(1), Status: models.StatusConfirmed,
+			Token: models.TokenIFIL, Source: models.SourceExternal, LogIndex: &first},
+		{Hash: multiHash, Sender: receiver, Receiver: sender, Amount: decimal.NewFromInt(2), Status: models.StatusConfirmed,
+			Token: models.TokenIFIL, Source: models.SourceExternal, LogIndex: &second},
+	}
+	imported, err := driver.ImportTransactions(ctx, sender, events, 130)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), imported)
+	imported, err = driver.ImportTransactions(ctx, sender, events, 130)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), imported)
+
+	// transactions submitted through the service are not imported again, other
+	// transfers of them are, like the iFIL minted for a deposit
+	submitted := models.Transaction{Hash: labeledHash, Sender: sender, Receiver: receiver, Amount: decimal.NewFromInt(1),
+		Status: models.StatusConfirmed, Source: models.SourceExternal}
+	minted := events[0]
+	minted.Hash = labeledHash
+	minted.Sender = "0x0000000000000000000000000000000000000000"
+	imported, err = driver.ImportTransactions(ctx, sender, []models.Transaction{submitted, minted}, 130)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), imported)
+
+	cursor, err := driver.GetBlockCursor(ctx, "incoming")
+	require.NoError(t, err)
+	require.Equal(t, uint64(120), cursor.LastBlock)
+
+	txs, err = driver.GetTransactions(ctx, TransactionFilter{Receiver: receiver, Direction: models.DirectionIncoming})
+	require.NoError(t, err)
+	require.Len(t, txs, 3)
+	hashes := []string{txs[0].Hash, txs[1].Hash, txs[2].Hash}
+	require.ElementsMatch(t, []string{incomingHash, incomingTokenHash, incomingTokenHash}, hashes)
+
+	require.NoError(t, driver.DeleteWatchedAddress(ctx, receiver))
+	require.ErrorIs(t, driver.DeleteWatchedAddress(ctx, receiver), ErrWatchedAddressNotFound)
+
+	// register a token, symbols are matched case-insensitively
+	const usdfc = "0xb3042734b608a1b16e9e86b374a3f3e389b4cdf0"
+	require.NoError(t, driver.CreateRegisteredToken(ctx, &models.RegisteredToken{Address: usdfc, Symbol: "USDFC", Decimals: 6}))
+	require.ErrorIs(t, driver.CreateRegisteredToken(ctx, &models.RegisteredToken{Address: receiver, Symbol: "usdfc", Decimals: 6}), ErrTokenExists)
+
+	token, err := driver.GetRegisteredToken(ctx, "UsdFC")
+	require.NoError(t, err)
+	require.Equal(t, usdfc, token.Address)
+	require.Equal(t, uint8(6), token.Decimals)
+
+	require.NoError(t, driver.DeleteRegisteredToken(ctx, "usdfc"))
+	_, err = driver.GetRegisteredToken(ctx, "USDFC")
+	require.ErrorIs(t, err, ErrTokenNotFound)
+
+	// an abandoned idempotency key is taken over by the same request only
+	const idempotencyKey = "3f1c9a52-transfer"
+	_, reserved, err := driver.ReserveIdempotencyKey(ctx, idempotencyKey, "fingerprint", time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	require.True(t, reserved)
+	_, reserved, err = driver.ReserveIdempotencyKey(ctx, idempotencyKey, "fingerprint", time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	require.False(t, reserved)
+	record, reserved, err := driver.ReserveIdempotencyKey(ctx, idempotencyKey, "other", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.False(t, reserved)
+	require.Equal(t, "fingerprint", record.Fingerprint)
+	_, reserved, err = driver.ReserveIdempotencyKey(ctx, idempotencyKey, "fingerprint", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	// the key is completed with its transaction and never taken over afterwards
+	const idempotentHash = "0x3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f708192a"
+	key := idempotencyKey
+	require.NoError(t, driver.SaveTransaction(&models.Transaction{
+		Hash: idempotentHash, Sender: sender, Receiver: receiver, Amount: decimal.NewFromInt(1), Status: models.StatusQueued, IdempotencyKey: &key,
+	}))
+	record, reserved, err = driver.ReserveIdempotencyKey(ctx, idempotencyKey, "fingerprint", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.False(t, reserved)
+	require.Equal(t, idempotentHash, *record.TxHash)
+
+	// queued nonces are known after a restart
+	queuedNonce, err := driver.MaxQueuedNonce(ctx, receiver)
+	require.NoError(t, err)
+	require.Nil(t, queuedNonce)
+
+	for i, hash := range []string{
+		"0x0e1f2a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f7",
+		"0x1f2a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f708",
+	} {
+		nonce := uint64(7 + i)
+		require.NoError(t, driver.SaveTransaction(&models.Transaction{
+			Hash: hash, Sender: receiver, Receiver: sender, Amount: decimal.NewFromInt(1), Status: models.StatusQueued, Nonce: &nonce,
+		}))
+	}
+	queuedNonce, err = driver.MaxQueuedNonce(ctx, receiver)
+	require.NoError(t, err)
+	require.Equal(t, uint64(8), *queuedNonce)
+
+	// a claimed transaction can't be cancelled, a cancelled one can't be claimed
+	require.NoError(t, driver.ClaimQueuedTransaction(ctx, "0x0e1f2a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f7"))
+	require.ErrorIs(t, driver.CancelQueuedTransaction(ctx, "0x0e1f2a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f7"), ErrTxNotFound)
+	require.NoError(t, driver.CancelQueuedTransaction(ctx, "0x1f2a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f708"))
+	require.ErrorIs(t, driver.ClaimQueuedTransaction(ctx, "0x1f2a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f708"), ErrTxNotFound)
+	cancelled, err := driver.GetTransaction(ctx, "0x1f2a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f708")
+	require.NoError(t, err)
+	require.Equal(t, models.StatusCancelled, cancelled.Status)
+
+	// an open approval covering the amount is found once
+	_, err = driver.GetOpenApproval(ctx, receiver, decimal.NewFromInt(5))
+	require.ErrorIs(t, err, ErrTxNotFound)
+	require.NoError(t, driver.SaveTransaction(&models.Transaction{
+		Hash: "0x2a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f70819", Sender: receiver, Receiver: sender,
+		Amount: decimal.NewFromInt(10), Status: models.StatusPending, Kind: models.KindApproval,
+	}))
+	approval, err := driver.GetOpenApproval(ctx, receiver, decimal.NewFromInt(5))
+	require.NoError(t, err)
+	require.Equal(t, "0x2a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f70819", approval.Hash)
+	_, err = driver.GetOpenApproval(ctx, receiver, decimal.NewFromInt(11))
+	require.ErrorIs(t, err, ErrTxNotFound)
 }
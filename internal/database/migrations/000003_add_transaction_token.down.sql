@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_transactions_token;
+ALTER TABLE transactions DROP COLUMN IF EXISTS token;
\ No newline at end of file
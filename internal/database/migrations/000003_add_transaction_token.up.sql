@@ -0,0 +1,3 @@
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS token VARCHAR(32) NOT NULL DEFAULT 'FIL';
+
+CREATE INDEX IF NOT EXISTS idx_transactions_token ON transactions(token);
\ No newline at end of file
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_transactions_replaces;
+ALTER TABLE transactions DROP COLUMN IF EXISTS replaces;
+
+UPDATE transactions SET status = 'failed' WHERE status = 'replaced';
+ALTER TYPE tx_status RENAME TO tx_status_old;
+CREATE TYPE tx_status AS ENUM ('pending', 'confirmed', 'failed');
+ALTER TABLE transactions ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE transactions ALTER COLUMN status TYPE tx_status USING status::text::tx_status;
+ALTER TABLE transactions ALTER COLUMN status SET DEFAULT 'pending';
+DROP TYPE tx_status_old;
@@ -0,0 +1,5 @@
+ALTER TYPE tx_status ADD VALUE IF NOT EXISTS 'replaced';
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS replaces VARCHAR(66);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_replaces ON transactions(replaces);
@@ -0,0 +1,9 @@
+ALTER TABLE transactions DROP COLUMN IF EXISTS kind;
+
+UPDATE transactions SET status = 'replaced' WHERE status = 'cancelled';
+ALTER TYPE tx_status RENAME TO tx_status_old;
+CREATE TYPE tx_status AS ENUM ('pending', 'confirmed', 'failed', 'replaced');
+ALTER TABLE transactions ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE transactions ALTER COLUMN status TYPE tx_status USING status::text::tx_status;
+ALTER TABLE transactions ALTER COLUMN status SET DEFAULT 'pending';
+DROP TYPE tx_status_old;
@@ -0,0 +1,3 @@
+ALTER TYPE tx_status ADD VALUE IF NOT EXISTS 'cancelled';
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS kind VARCHAR(32) NOT NULL DEFAULT 'transfer';
@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS idx_transactions_sender_nonce;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS nonce,
+    DROP COLUMN IF EXISTS gas_limit,
+    DROP COLUMN IF EXISTS gas_fee_cap,
+    DROP COLUMN IF EXISTS gas_tip_cap,
+    DROP COLUMN IF EXISTS chain_id,
+    DROP COLUMN IF EXISTS tx_type,
+    DROP COLUMN IF EXISTS block_number,
+    DROP COLUMN IF EXISTS block_hash,
+    DROP COLUMN IF EXISTS gas_used,
+    DROP COLUMN IF EXISTS effective_gas_price;
@@ -0,0 +1,13 @@
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS nonce BIGINT,
+    ADD COLUMN IF NOT EXISTS gas_limit BIGINT,
+    ADD COLUMN IF NOT EXISTS gas_fee_cap NUMERIC(78, 0),
+    ADD COLUMN IF NOT EXISTS gas_tip_cap NUMERIC(78, 0),
+    ADD COLUMN IF NOT EXISTS chain_id BIGINT,
+    ADD COLUMN IF NOT EXISTS tx_type SMALLINT,
+    ADD COLUMN IF NOT EXISTS block_number BIGINT,
+    ADD COLUMN IF NOT EXISTS block_hash VARCHAR(66),
+    ADD COLUMN IF NOT EXISTS gas_used BIGINT,
+    ADD COLUMN IF NOT EXISTS effective_gas_price NUMERIC(78, 0);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_sender_nonce ON transactions(sender, nonce);
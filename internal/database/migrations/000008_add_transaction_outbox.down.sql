@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS idx_transactions_status;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS raw_tx,
+    DROP COLUMN IF EXISTS broadcast_attempts,
+    DROP COLUMN IF EXISTS last_error;
+
+UPDATE transactions SET status = 'failed' WHERE status = 'queued';
+ALTER TYPE tx_status RENAME TO tx_status_old;
+CREATE TYPE tx_status AS ENUM ('pending', 'confirmed', 'failed', 'replaced', 'cancelled');
+ALTER TABLE transactions ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE transactions ALTER COLUMN status TYPE tx_status USING status::text::tx_status;
+ALTER TABLE transactions ALTER COLUMN status SET DEFAULT 'pending';
+DROP TYPE tx_status_old;
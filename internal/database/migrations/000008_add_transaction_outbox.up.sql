@@ -0,0 +1,8 @@
+ALTER TYPE tx_status ADD VALUE IF NOT EXISTS 'queued';
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS raw_tx TEXT,
+    ADD COLUMN IF NOT EXISTS broadcast_attempts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS last_error TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_status ON transactions(status);
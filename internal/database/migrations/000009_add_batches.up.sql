@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS batches (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    sender VARCHAR(42) NOT NULL,
+    token VARCHAR(32) NOT NULL DEFAULT 'FIL',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS batch_id BIGINT REFERENCES batches(id);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_batch_id ON transactions(batch_id);
@@ -0,0 +1 @@
+-- the widening of transaction amounts moved to 000024_widen_transaction_amounts
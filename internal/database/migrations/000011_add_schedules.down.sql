@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_transactions_schedule_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS schedule_id;
+
+DROP TABLE IF EXISTS schedules;
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS schedules (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    sender VARCHAR(42) NOT NULL,
+    receiver VARCHAR(42) NOT NULL,
+    amount DECIMAL(30,18) NOT NULL,
+    token VARCHAR(32) NOT NULL DEFAULT 'FIL',
+    speed VARCHAR(16) NOT NULL DEFAULT '',
+    cron VARCHAR(255),
+    next_run_at TIMESTAMPTZ,
+    status VARCHAR(16) NOT NULL DEFAULT 'active',
+    last_run_at TIMESTAMPTZ,
+    last_tx_hash VARCHAR(66),
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_schedules_due ON schedules(status, next_run_at);
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS schedule_id BIGINT REFERENCES schedules(id);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_schedule_id ON transactions(schedule_id);
@@ -0,0 +1,11 @@
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS drop_reason,
+    DROP COLUMN IF EXISTS last_seen_at;
+
+UPDATE transactions SET status = 'failed' WHERE status = 'dropped';
+ALTER TYPE tx_status RENAME TO tx_status_old;
+CREATE TYPE tx_status AS ENUM ('queued', 'pending', 'confirmed', 'failed', 'replaced', 'cancelled');
+ALTER TABLE transactions ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE transactions ALTER COLUMN status TYPE tx_status USING status::text::tx_status;
+ALTER TABLE transactions ALTER COLUMN status SET DEFAULT 'pending';
+DROP TYPE tx_status_old;
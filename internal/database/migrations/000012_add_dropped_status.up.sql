@@ -0,0 +1,5 @@
+ALTER TYPE tx_status ADD VALUE IF NOT EXISTS 'dropped';
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS drop_reason TEXT,
+    ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMPTZ;
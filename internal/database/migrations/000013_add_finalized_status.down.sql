@@ -0,0 +1,7 @@
+UPDATE transactions SET status = 'confirmed' WHERE status = 'finalized';
+ALTER TYPE tx_status RENAME TO tx_status_old;
+CREATE TYPE tx_status AS ENUM ('queued', 'pending', 'confirmed', 'failed', 'replaced', 'cancelled', 'dropped');
+ALTER TABLE transactions ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE transactions ALTER COLUMN status TYPE tx_status USING status::text::tx_status;
+ALTER TABLE transactions ALTER COLUMN status SET DEFAULT 'pending';
+DROP TYPE tx_status_old;
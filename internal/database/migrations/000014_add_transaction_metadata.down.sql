@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_transactions_reference;
+DROP INDEX IF EXISTS idx_transactions_labels;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS memo,
+    DROP COLUMN IF EXISTS labels,
+    DROP COLUMN IF EXISTS reference;
@@ -0,0 +1,7 @@
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS memo TEXT,
+    ADD COLUMN IF NOT EXISTS labels TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS reference VARCHAR(255);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_labels ON transactions USING GIN (labels);
+CREATE INDEX IF NOT EXISTS idx_transactions_reference ON transactions(reference);
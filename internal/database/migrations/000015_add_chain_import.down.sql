@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS import_checkpoints;
+
+DELETE FROM transactions WHERE source = 'external';
+ALTER TABLE transactions DROP COLUMN IF EXISTS source;
@@ -0,0 +1,7 @@
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS source VARCHAR(16) NOT NULL DEFAULT 'service';
+
+CREATE TABLE IF NOT EXISTS import_checkpoints (
+    address VARCHAR(42) PRIMARY KEY,
+    last_block BIGINT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
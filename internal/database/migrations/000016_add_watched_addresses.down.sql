@@ -0,0 +1,4 @@
+ALTER TABLE transactions DROP COLUMN IF EXISTS direction;
+
+DROP TABLE IF EXISTS block_cursors;
+DROP TABLE IF EXISTS watched_addresses;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS watched_addresses (
+    address VARCHAR(42) PRIMARY KEY,
+    label VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS block_cursors (
+    name VARCHAR(64) PRIMARY KEY,
+    last_block BIGINT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS direction VARCHAR(16) NOT NULL DEFAULT 'outgoing';
+UPDATE transactions SET direction = 'incoming'
+    WHERE source = 'external' AND receiver IN (SELECT address FROM import_checkpoints) AND sender <> receiver;
@@ -0,0 +1,5 @@
+ALTER TABLE schedules DROP COLUMN IF EXISTS token_address;
+ALTER TABLE batches DROP COLUMN IF EXISTS token_address;
+ALTER TABLE transactions DROP COLUMN IF EXISTS token_address;
+
+DROP TABLE IF EXISTS registered_tokens;
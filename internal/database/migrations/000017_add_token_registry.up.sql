@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS registered_tokens (
+    address VARCHAR(42) PRIMARY KEY,
+    symbol VARCHAR(32) NOT NULL,
+    decimals SMALLINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_registered_tokens_symbol ON registered_tokens(LOWER(symbol));
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS token_address VARCHAR(42);
+ALTER TABLE batches ADD COLUMN IF NOT EXISTS token_address VARCHAR(42);
+ALTER TABLE schedules ADD COLUMN IF NOT EXISTS token_address VARCHAR(42);
@@ -0,0 +1,2 @@
+ALTER TABLE transactions DROP COLUMN IF EXISTS expected_token;
+ALTER TABLE transactions DROP COLUMN IF EXISTS expected_amount;
@@ -0,0 +1,2 @@
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS expected_amount NUMERIC(78,18);
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS expected_token VARCHAR(32);
@@ -0,0 +1,9 @@
+DELETE FROM transactions t
+    WHERE t.log_index IS NOT NULL
+      AND EXISTS (SELECT 1 FROM transactions o WHERE o.hash = t.hash AND o.id < t.id);
+
+DROP INDEX IF EXISTS idx_transactions_hash_log_index;
+DROP INDEX IF EXISTS idx_transactions_hash;
+ALTER TABLE transactions ADD CONSTRAINT transactions_hash_key UNIQUE (hash);
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS log_index;
@@ -0,0 +1,5 @@
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS log_index INTEGER;
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_hash_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_hash ON transactions(hash) WHERE log_index IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_hash_log_index ON transactions(hash, log_index) WHERE log_index IS NOT NULL;
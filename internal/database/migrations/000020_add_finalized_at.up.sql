@@ -0,0 +1,2 @@
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS finalized_at TIMESTAMPTZ;
+UPDATE transactions SET finalized_at = CURRENT_TIMESTAMP WHERE status = 'finalized';
@@ -0,0 +1,7 @@
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS idempotency_key VARCHAR(255);
+
+UPDATE transactions t SET idempotency_key = k.key
+FROM idempotency_keys k
+WHERE k.tx_hash = t.hash AND t.log_index IS NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_idempotency_key ON transactions(idempotency_key) WHERE idempotency_key IS NOT NULL;
@@ -0,0 +1 @@
+ALTER TYPE tx_status ADD VALUE IF NOT EXISTS 'broadcasting';
@@ -0,0 +1 @@
+ALTER TABLE schedules ALTER COLUMN amount TYPE DECIMAL(30,18);
@@ -0,0 +1,2 @@
+-- amounts are stored in attoFIL, DECIMAL(30,18) overflows from 10^12 attoFIL
+ALTER TABLE schedules ALTER COLUMN amount TYPE NUMERIC(78,18);
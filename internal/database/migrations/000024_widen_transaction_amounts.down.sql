@@ -0,0 +1 @@
+ALTER TABLE transactions ALTER COLUMN amount TYPE DECIMAL(30,18);
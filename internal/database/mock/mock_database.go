@@ -10,10 +10,13 @@
 package database
 
 import (
+	database "app/internal/database"
 	models "app/internal/database/models"
 	context "context"
 	reflect "reflect"
+	time "time"
 
+	decimal "github.com/shopspring/decimal"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,19 +44,559 @@ func (m *MockDatabase) EXPECT() *MockDatabaseMockRecorder {
 	return m.recorder
 }
 
+// CancelQueuedTransaction mocks base method.
+func (m *MockDatabase) CancelQueuedTransaction(ctx context.Context, hash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelQueuedTransaction", ctx, hash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelQueuedTransaction indicates an expected call of CancelQueuedTransaction.
+func (mr *MockDatabaseMockRecorder) CancelQueuedTransaction(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelQueuedTransaction", reflect.TypeOf((*MockDatabase)(nil).CancelQueuedTransaction), ctx, hash)
+}
+
+// ClaimQueuedTransaction mocks base method.
+func (m *MockDatabase) ClaimQueuedTransaction(ctx context.Context, hash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimQueuedTransaction", ctx, hash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClaimQueuedTransaction indicates an expected call of ClaimQueuedTransaction.
+func (mr *MockDatabaseMockRecorder) ClaimQueuedTransaction(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimQueuedTransaction", reflect.TypeOf((*MockDatabase)(nil).ClaimQueuedTransaction), ctx, hash)
+}
+
+// ClaimScheduleRun mocks base method.
+func (m *MockDatabase) ClaimScheduleRun(ctx context.Context, id uint64, runAt time.Time, next *time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimScheduleRun", ctx, id, runAt, next)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimScheduleRun indicates an expected call of ClaimScheduleRun.
+func (mr *MockDatabaseMockRecorder) ClaimScheduleRun(ctx, id, runAt, next any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimScheduleRun", reflect.TypeOf((*MockDatabase)(nil).ClaimScheduleRun), ctx, id, runAt, next)
+}
+
+// CreateBatch mocks base method.
+func (m *MockDatabase) CreateBatch(ctx context.Context, batch *models.Batch) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBatch", ctx, batch)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateBatch indicates an expected call of CreateBatch.
+func (mr *MockDatabaseMockRecorder) CreateBatch(ctx, batch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockDatabase)(nil).CreateBatch), ctx, batch)
+}
+
+// CreateRegisteredToken mocks base method.
+func (m *MockDatabase) CreateRegisteredToken(ctx context.Context, token *models.RegisteredToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRegisteredToken", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRegisteredToken indicates an expected call of CreateRegisteredToken.
+func (mr *MockDatabaseMockRecorder) CreateRegisteredToken(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRegisteredToken", reflect.TypeOf((*MockDatabase)(nil).CreateRegisteredToken), ctx, token)
+}
+
+// CreateSchedule mocks base method.
+func (m *MockDatabase) CreateSchedule(ctx context.Context, schedule *models.Schedule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSchedule", ctx, schedule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSchedule indicates an expected call of CreateSchedule.
+func (mr *MockDatabaseMockRecorder) CreateSchedule(ctx, schedule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSchedule", reflect.TypeOf((*MockDatabase)(nil).CreateSchedule), ctx, schedule)
+}
+
+// CreateWatchedAddress mocks base method.
+func (m *MockDatabase) CreateWatchedAddress(ctx context.Context, watched *models.WatchedAddress) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWatchedAddress", ctx, watched)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWatchedAddress indicates an expected call of CreateWatchedAddress.
+func (mr *MockDatabaseMockRecorder) CreateWatchedAddress(ctx, watched any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWatchedAddress", reflect.TypeOf((*MockDatabase)(nil).CreateWatchedAddress), ctx, watched)
+}
+
+// DeleteRegisteredToken mocks base method.
+func (m *MockDatabase) DeleteRegisteredToken(ctx context.Context, symbol string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRegisteredToken", ctx, symbol)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRegisteredToken indicates an expected call of DeleteRegisteredToken.
+func (mr *MockDatabaseMockRecorder) DeleteRegisteredToken(ctx, symbol any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRegisteredToken", reflect.TypeOf((*MockDatabase)(nil).DeleteRegisteredToken), ctx, symbol)
+}
+
+// DeleteWatchedAddress mocks base method.
+func (m *MockDatabase) DeleteWatchedAddress(ctx context.Context, address string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWatchedAddress", ctx, address)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWatchedAddress indicates an expected call of DeleteWatchedAddress.
+func (mr *MockDatabaseMockRecorder) DeleteWatchedAddress(ctx, address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWatchedAddress", reflect.TypeOf((*MockDatabase)(nil).DeleteWatchedAddress), ctx, address)
+}
+
+// FinalizeTransaction mocks base method.
+func (m *MockDatabase) FinalizeTransaction(ctx context.Context, hash string, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinalizeTransaction", ctx, hash, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinalizeTransaction indicates an expected call of FinalizeTransaction.
+func (mr *MockDatabaseMockRecorder) FinalizeTransaction(ctx, hash, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinalizeTransaction", reflect.TypeOf((*MockDatabase)(nil).FinalizeTransaction), ctx, hash, at)
+}
+
+// GetBatch mocks base method.
+func (m *MockDatabase) GetBatch(ctx context.Context, id uint64) (*models.Batch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBatch", ctx, id)
+	ret0, _ := ret[0].(*models.Batch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBatch indicates an expected call of GetBatch.
+func (mr *MockDatabaseMockRecorder) GetBatch(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBatch", reflect.TypeOf((*MockDatabase)(nil).GetBatch), ctx, id)
+}
+
+// GetBatchTransactions mocks base method.
+func (m *MockDatabase) GetBatchTransactions(ctx context.Context, id uint64) ([]models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBatchTransactions", ctx, id)
+	ret0, _ := ret[0].([]models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBatchTransactions indicates an expected call of GetBatchTransactions.
+func (mr *MockDatabaseMockRecorder) GetBatchTransactions(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBatchTransactions", reflect.TypeOf((*MockDatabase)(nil).GetBatchTransactions), ctx, id)
+}
+
+// GetBlockCursor mocks base method.
+func (m *MockDatabase) GetBlockCursor(ctx context.Context, name string) (*models.BlockCursor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockCursor", ctx, name)
+	ret0, _ := ret[0].(*models.BlockCursor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockCursor indicates an expected call of GetBlockCursor.
+func (mr *MockDatabaseMockRecorder) GetBlockCursor(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockCursor", reflect.TypeOf((*MockDatabase)(nil).GetBlockCursor), ctx, name)
+}
+
+// GetConfirmedTransactions mocks base method.
+func (m *MockDatabase) GetConfirmedTransactions(ctx context.Context) ([]models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfirmedTransactions", ctx)
+	ret0, _ := ret[0].([]models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfirmedTransactions indicates an expected call of GetConfirmedTransactions.
+func (mr *MockDatabaseMockRecorder) GetConfirmedTransactions(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfirmedTransactions", reflect.TypeOf((*MockDatabase)(nil).GetConfirmedTransactions), ctx)
+}
+
+// GetDueSchedules mocks base method.
+func (m *MockDatabase) GetDueSchedules(ctx context.Context, now time.Time) ([]models.Schedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueSchedules", ctx, now)
+	ret0, _ := ret[0].([]models.Schedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueSchedules indicates an expected call of GetDueSchedules.
+func (mr *MockDatabaseMockRecorder) GetDueSchedules(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueSchedules", reflect.TypeOf((*MockDatabase)(nil).GetDueSchedules), ctx, now)
+}
+
+// GetImportCheckpoint mocks base method.
+func (m *MockDatabase) GetImportCheckpoint(ctx context.Context, address string) (*models.ImportCheckpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImportCheckpoint", ctx, address)
+	ret0, _ := ret[0].(*models.ImportCheckpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImportCheckpoint indicates an expected call of GetImportCheckpoint.
+func (mr *MockDatabaseMockRecorder) GetImportCheckpoint(ctx, address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImportCheckpoint", reflect.TypeOf((*MockDatabase)(nil).GetImportCheckpoint), ctx, address)
+}
+
+// GetOpenApproval mocks base method.
+func (m *MockDatabase) GetOpenApproval(ctx context.Context, sender string, amount decimal.Decimal) (*models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOpenApproval", ctx, sender, amount)
+	ret0, _ := ret[0].(*models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpenApproval indicates an expected call of GetOpenApproval.
+func (mr *MockDatabaseMockRecorder) GetOpenApproval(ctx, sender, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenApproval", reflect.TypeOf((*MockDatabase)(nil).GetOpenApproval), ctx, sender, amount)
+}
+
+// GetPendingTransactions mocks base method.
+func (m *MockDatabase) GetPendingTransactions(ctx context.Context) ([]models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingTransactions", ctx)
+	ret0, _ := ret[0].([]models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingTransactions indicates an expected call of GetPendingTransactions.
+func (mr *MockDatabaseMockRecorder) GetPendingTransactions(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingTransactions", reflect.TypeOf((*MockDatabase)(nil).GetPendingTransactions), ctx)
+}
+
+// GetQueuedTransactions mocks base method.
+func (m *MockDatabase) GetQueuedTransactions(ctx context.Context) ([]models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQueuedTransactions", ctx)
+	ret0, _ := ret[0].([]models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQueuedTransactions indicates an expected call of GetQueuedTransactions.
+func (mr *MockDatabaseMockRecorder) GetQueuedTransactions(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQueuedTransactions", reflect.TypeOf((*MockDatabase)(nil).GetQueuedTransactions), ctx)
+}
+
+// GetRegisteredToken mocks base method.
+func (m *MockDatabase) GetRegisteredToken(ctx context.Context, symbol string) (*models.RegisteredToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRegisteredToken", ctx, symbol)
+	ret0, _ := ret[0].(*models.RegisteredToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRegisteredToken indicates an expected call of GetRegisteredToken.
+func (mr *MockDatabaseMockRecorder) GetRegisteredToken(ctx, symbol any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegisteredToken", reflect.TypeOf((*MockDatabase)(nil).GetRegisteredToken), ctx, symbol)
+}
+
+// GetRegisteredTokens mocks base method.
+func (m *MockDatabase) GetRegisteredTokens(ctx context.Context) ([]models.RegisteredToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRegisteredTokens", ctx)
+	ret0, _ := ret[0].([]models.RegisteredToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRegisteredTokens indicates an expected call of GetRegisteredTokens.
+func (mr *MockDatabaseMockRecorder) GetRegisteredTokens(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegisteredTokens", reflect.TypeOf((*MockDatabase)(nil).GetRegisteredTokens), ctx)
+}
+
+// GetSchedule mocks base method.
+func (m *MockDatabase) GetSchedule(ctx context.Context, id uint64) (*models.Schedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSchedule", ctx, id)
+	ret0, _ := ret[0].(*models.Schedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSchedule indicates an expected call of GetSchedule.
+func (mr *MockDatabaseMockRecorder) GetSchedule(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSchedule", reflect.TypeOf((*MockDatabase)(nil).GetSchedule), ctx, id)
+}
+
+// GetSchedules mocks base method.
+func (m *MockDatabase) GetSchedules(ctx context.Context) ([]models.Schedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSchedules", ctx)
+	ret0, _ := ret[0].([]models.Schedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSchedules indicates an expected call of GetSchedules.
+func (mr *MockDatabaseMockRecorder) GetSchedules(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSchedules", reflect.TypeOf((*MockDatabase)(nil).GetSchedules), ctx)
+}
+
+// GetTransaction mocks base method.
+func (m *MockDatabase) GetTransaction(ctx context.Context, hash string) (*models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransaction", ctx, hash)
+	ret0, _ := ret[0].(*models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransaction indicates an expected call of GetTransaction.
+func (mr *MockDatabaseMockRecorder) GetTransaction(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransaction", reflect.TypeOf((*MockDatabase)(nil).GetTransaction), ctx, hash)
+}
+
 // GetTransactions mocks base method.
-func (m *MockDatabase) GetTransactions(ctx context.Context, sender, receiver string, offset int) ([]models.Transaction, error) {
+func (m *MockDatabase) GetTransactions(ctx context.Context, filter database.TransactionFilter) ([]models.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransactions", ctx, sender, receiver, offset)
+	ret := m.ctrl.Call(m, "GetTransactions", ctx, filter)
 	ret0, _ := ret[0].([]models.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTransactions indicates an expected call of GetTransactions.
-func (mr *MockDatabaseMockRecorder) GetTransactions(ctx, sender, receiver, offset any) *gomock.Call {
+func (mr *MockDatabaseMockRecorder) GetTransactions(ctx, filter any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactions", reflect.TypeOf((*MockDatabase)(nil).GetTransactions), ctx, sender, receiver, offset)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactions", reflect.TypeOf((*MockDatabase)(nil).GetTransactions), ctx, filter)
+}
+
+// GetWatchedAddress mocks base method.
+func (m *MockDatabase) GetWatchedAddress(ctx context.Context, address string) (*models.WatchedAddress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWatchedAddress", ctx, address)
+	ret0, _ := ret[0].(*models.WatchedAddress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWatchedAddress indicates an expected call of GetWatchedAddress.
+func (mr *MockDatabaseMockRecorder) GetWatchedAddress(ctx, address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWatchedAddress", reflect.TypeOf((*MockDatabase)(nil).GetWatchedAddress), ctx, address)
+}
+
+// GetWatchedAddresses mocks base method.
+func (m *MockDatabase) GetWatchedAddresses(ctx context.Context) ([]models.WatchedAddress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWatchedAddresses", ctx)
+	ret0, _ := ret[0].([]models.WatchedAddress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWatchedAddresses indicates an expected call of GetWatchedAddresses.
+func (mr *MockDatabaseMockRecorder) GetWatchedAddresses(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWatchedAddresses", reflect.TypeOf((*MockDatabase)(nil).GetWatchedAddresses), ctx)
+}
+
+// ImportTransactions mocks base method.
+func (m *MockDatabase) ImportTransactions(ctx context.Context, address string, txs []models.Transaction, lastBlock uint64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportTransactions", ctx, address, txs, lastBlock)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportTransactions indicates an expected call of ImportTransactions.
+func (mr *MockDatabaseMockRecorder) ImportTransactions(ctx, address, txs, lastBlock any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportTransactions", reflect.TypeOf((*MockDatabase)(nil).ImportTransactions), ctx, address, txs, lastBlock)
+}
+
+// MarkDropped mocks base method.
+func (m *MockDatabase) MarkDropped(ctx context.Context, hash, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkDropped", ctx, hash, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkDropped indicates an expected call of MarkDropped.
+func (mr *MockDatabaseMockRecorder) MarkDropped(ctx, hash, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDropped", reflect.TypeOf((*MockDatabase)(nil).MarkDropped), ctx, hash, reason)
+}
+
+// MarkSeen mocks base method.
+func (m *MockDatabase) MarkSeen(ctx context.Context, hash string, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkSeen", ctx, hash, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkSeen indicates an expected call of MarkSeen.
+func (mr *MockDatabaseMockRecorder) MarkSeen(ctx, hash, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkSeen", reflect.TypeOf((*MockDatabase)(nil).MarkSeen), ctx, hash, at)
+}
+
+// MarkSuperseded mocks base method.
+func (m *MockDatabase) MarkSuperseded(ctx context.Context, original, winner string, status models.TransactionStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkSuperseded", ctx, original, winner, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkSuperseded indicates an expected call of MarkSuperseded.
+func (mr *MockDatabaseMockRecorder) MarkSuperseded(ctx, original, winner, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkSuperseded", reflect.TypeOf((*MockDatabase)(nil).MarkSuperseded), ctx, original, winner, status)
+}
+
+// MaxQueuedNonce mocks base method.
+func (m *MockDatabase) MaxQueuedNonce(ctx context.Context, sender string) (*uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MaxQueuedNonce", ctx, sender)
+	ret0, _ := ret[0].(*uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MaxQueuedNonce indicates an expected call of MaxQueuedNonce.
+func (mr *MockDatabaseMockRecorder) MaxQueuedNonce(ctx, sender any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MaxQueuedNonce", reflect.TypeOf((*MockDatabase)(nil).MaxQueuedNonce), ctx, sender)
+}
+
+// RecordBroadcastFailure mocks base method.
+func (m *MockDatabase) RecordBroadcastFailure(ctx context.Context, hash, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordBroadcastFailure", ctx, hash, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordBroadcastFailure indicates an expected call of RecordBroadcastFailure.
+func (mr *MockDatabaseMockRecorder) RecordBroadcastFailure(ctx, hash, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordBroadcastFailure", reflect.TypeOf((*MockDatabase)(nil).RecordBroadcastFailure), ctx, hash, reason)
+}
+
+// RecordIncomingTransactions mocks base method.
+func (m *MockDatabase) RecordIncomingTransactions(ctx context.Context, cursor string, txs []models.Transaction, lastBlock uint64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordIncomingTransactions", ctx, cursor, txs, lastBlock)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordIncomingTransactions indicates an expected call of RecordIncomingTransactions.
+func (mr *MockDatabaseMockRecorder) RecordIncomingTransactions(ctx, cursor, txs, lastBlock any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordIncomingTransactions", reflect.TypeOf((*MockDatabase)(nil).RecordIncomingTransactions), ctx, cursor, txs, lastBlock)
+}
+
+// RecordScheduleRun mocks base method.
+func (m *MockDatabase) RecordScheduleRun(ctx context.Context, id uint64, txHash, runErr *string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordScheduleRun", ctx, id, txHash, runErr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordScheduleRun indicates an expected call of RecordScheduleRun.
+func (mr *MockDatabaseMockRecorder) RecordScheduleRun(ctx, id, txHash, runErr any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordScheduleRun", reflect.TypeOf((*MockDatabase)(nil).RecordScheduleRun), ctx, id, txHash, runErr)
+}
+
+// ReleaseIdempotencyKey mocks base method.
+func (m *MockDatabase) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseIdempotencyKey", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseIdempotencyKey indicates an expected call of ReleaseIdempotencyKey.
+func (mr *MockDatabaseMockRecorder) ReleaseIdempotencyKey(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseIdempotencyKey", reflect.TypeOf((*MockDatabase)(nil).ReleaseIdempotencyKey), ctx, key)
+}
+
+// ReserveIdempotencyKey mocks base method.
+func (m *MockDatabase) ReserveIdempotencyKey(ctx context.Context, key, fingerprint string, staleBefore time.Time) (*models.IdempotencyKey, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveIdempotencyKey", ctx, key, fingerprint, staleBefore)
+	ret0, _ := ret[0].(*models.IdempotencyKey)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ReserveIdempotencyKey indicates an expected call of ReserveIdempotencyKey.
+func (mr *MockDatabaseMockRecorder) ReserveIdempotencyKey(ctx, key, fingerprint, staleBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveIdempotencyKey", reflect.TypeOf((*MockDatabase)(nil).ReserveIdempotencyKey), ctx, key, fingerprint, staleBefore)
+}
+
+// RevertToPending mocks base method.
+func (m *MockDatabase) RevertToPending(ctx context.Context, hash string, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevertToPending", ctx, hash, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevertToPending indicates an expected call of RevertToPending.
+func (mr *MockDatabaseMockRecorder) RevertToPending(ctx, hash, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevertToPending", reflect.TypeOf((*MockDatabase)(nil).RevertToPending), ctx, hash, at)
 }
 
 // SaveTransaction mocks base method.
@@ -69,3 +612,59 @@ func (mr *MockDatabaseMockRecorder) SaveTransaction(tx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTransaction", reflect.TypeOf((*MockDatabase)(nil).SaveTransaction), tx)
 }
+
+// UpdateSchedule mocks base method.
+func (m *MockDatabase) UpdateSchedule(ctx context.Context, schedule *models.Schedule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSchedule", ctx, schedule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSchedule indicates an expected call of UpdateSchedule.
+func (mr *MockDatabaseMockRecorder) UpdateSchedule(ctx, schedule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSchedule", reflect.TypeOf((*MockDatabase)(nil).UpdateSchedule), ctx, schedule)
+}
+
+// UpdateTransactionReceipt mocks base method.
+func (m *MockDatabase) UpdateTransactionReceipt(ctx context.Context, hash string, status models.TransactionStatus, receipt models.Receipt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTransactionReceipt", ctx, hash, status, receipt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTransactionReceipt indicates an expected call of UpdateTransactionReceipt.
+func (mr *MockDatabaseMockRecorder) UpdateTransactionReceipt(ctx, hash, status, receipt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransactionReceipt", reflect.TypeOf((*MockDatabase)(nil).UpdateTransactionReceipt), ctx, hash, status, receipt)
+}
+
+// UpdateTransactionStatus mocks base method.
+func (m *MockDatabase) UpdateTransactionStatus(ctx context.Context, hash string, status models.TransactionStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTransactionStatus", ctx, hash, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTransactionStatus indicates an expected call of UpdateTransactionStatus.
+func (mr *MockDatabaseMockRecorder) UpdateTransactionStatus(ctx, hash, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransactionStatus", reflect.TypeOf((*MockDatabase)(nil).UpdateTransactionStatus), ctx, hash, status)
+}
+
+// UpdateWatchedAddress mocks base method.
+func (m *MockDatabase) UpdateWatchedAddress(ctx context.Context, watched *models.WatchedAddress) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWatchedAddress", ctx, watched)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWatchedAddress indicates an expected call of UpdateWatchedAddress.
+func (mr *MockDatabaseMockRecorder) UpdateWatchedAddress(ctx, watched any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWatchedAddress", reflect.TypeOf((*MockDatabase)(nil).UpdateWatchedAddress), ctx, watched)
+}
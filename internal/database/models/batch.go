@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Batch groups the transfers of one sender submitted in a single request.
+type Batch struct {
+	ID     uint64 `gorm:"primaryKey;autoIncrement"`
+	Sender string
+	Token  Token
+	// TokenAddress is set for registered ERC-20 tokens
+	TokenAddress *string
+	CreatedAt    time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+}
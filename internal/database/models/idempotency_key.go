@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// IdempotencyKey records a client supplied key of a submission. TxHash is empty
+// while the submission is in progress.
+type IdempotencyKey struct {
+	Key         string `gorm:"primaryKey"`
+	Fingerprint string
+	TxHash      *string
+	CreatedAt   time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+}
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// ImportCheckpoint is the highest block scanned for transfers of an address.
+type ImportCheckpoint struct {
+	Address   string `gorm:"primaryKey"`
+	LastBlock uint64
+	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+}
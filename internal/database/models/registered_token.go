@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// RegisteredToken is an ERC-20 token which can be transferred besides FIL and iFIL.
+type RegisteredToken struct {
+	Address   string `gorm:"primaryKey"`
+	Symbol    Token
+	Decimals  uint8
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+type ScheduleStatus string
+
+const (
+	ScheduleActive    ScheduleStatus = "active"
+	ScheduleCompleted ScheduleStatus = "completed"
+	ScheduleCancelled ScheduleStatus = "cancelled"
+)
+
+// Schedule is a transfer from a managed account which is sent once at NextRunAt,
+// or repeatedly if Cron is set.
+type Schedule struct {
+	ID       uint64 `gorm:"primaryKey;autoIncrement"`
+	Sender   string
+	Receiver string
+	Amount   decimal.Decimal `gorm:"type:numeric(78,18)"`
+	Token    Token           `gorm:"default:FIL"`
+	// TokenAddress is set for registered ERC-20 tokens, runs transfer this contract
+	// even if the symbol is unregistered meanwhile
+	TokenAddress *string
+	Speed        string
+	Cron         *string
+	NextRunAt    *time.Time
+	Status       ScheduleStatus `gorm:"default:active"`
+	LastRunAt    *time.Time
+	LastTxHash   *string
+	LastError    *string
+	CreatedAt    time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"github.com/lib/pq"
 	"github.com/shopspring/decimal"
 	"time"
 )
@@ -8,9 +9,54 @@ import (
 type TransactionStatus string
 
 const (
-	StatusPending   TransactionStatus = "pending"
-	StatusConfirmed TransactionStatus = "confirmed"
+	StatusQueued TransactionStatus = "queued"
+	// StatusBroadcasting is a queued transaction the broadcaster is sending right now
+	StatusBroadcasting TransactionStatus = "broadcasting"
+	StatusPending      TransactionStatus = "pending"
+	StatusConfirmed    TransactionStatus = "confirmed"
+	// StatusFinalized is a confirmed transaction whose block is deep enough to not be reorganised
+	StatusFinalized TransactionStatus = "finalized"
 	StatusFailed    TransactionStatus = "failed"
+	StatusReplaced  TransactionStatus = "replaced"
+	StatusCancelled TransactionStatus = "cancelled"
+	StatusDropped   TransactionStatus = "dropped"
+)
+
+type Token string
+
+const (
+	TokenFIL  Token = "FIL"
+	TokenIFIL Token = "iFIL"
+)
+
+type TransactionKind string
+
+const (
+	KindTransfer TransactionKind = "transfer"
+	KindCancel   TransactionKind = "cancel"
+	// KindDeposit stakes FIL in the Infinity Pool, which mints iFIL to the sender
+	KindDeposit TransactionKind = "deposit"
+	// KindApproval allows the Infinity Pool to burn iFIL of the sender
+	KindApproval TransactionKind = "approval"
+	// KindWithdrawal exchanges iFIL for FIL at the Infinity Pool
+	KindWithdrawal TransactionKind = "withdrawal"
+)
+
+type TransactionSource string
+
+const (
+	// SourceService marks transactions submitted through this service.
+	SourceService TransactionSource = "service"
+	// SourceExternal marks transactions imported from the chain.
+	SourceExternal TransactionSource = "external"
+)
+
+type TransactionDirection string
+
+const (
+	DirectionOutgoing TransactionDirection = "outgoing"
+	// DirectionIncoming marks transfers received by an address of ours from elsewhere.
+	DirectionIncoming TransactionDirection = "incoming"
 )
 
 type Transaction struct {
@@ -18,7 +64,69 @@ type Transaction struct {
 	Hash      string
 	Sender    string
 	Receiver  string
-	Amount    decimal.Decimal `gorm:"type:decimal(30,18)"`
+	Amount    decimal.Decimal `gorm:"type:numeric(78,18)"`
 	Timestamp time.Time       `gorm:"default:CURRENT_TIMESTAMP"`
 	Status    TransactionStatus
+	Token     Token `gorm:"default:FIL"`
+	// TokenAddress is the contract of a registered ERC-20 token, it keeps the
+	// history correct if the symbol is registered again for another contract.
+	TokenAddress *string
+	// Replaces is the hash of the original transaction if this one reuses its nonce.
+	Replaces  *string
+	Kind      TransactionKind      `gorm:"default:transfer"`
+	Source    TransactionSource    `gorm:"default:service"`
+	Direction TransactionDirection `gorm:"default:outgoing"`
+
+	// set at submission, empty for transactions stored before they were tracked
+	Nonce     *uint64
+	GasLimit  *uint64
+	GasFeeCap *decimal.Decimal `gorm:"type:numeric(78,0)"`
+	GasTipCap *decimal.Decimal `gorm:"type:numeric(78,0)"`
+	ChainID   *uint64
+	TxType    *uint8
+
+	// set from the receipt once the transaction is mined
+	BlockNumber       *uint64
+	BlockHash         *string
+	GasUsed           *uint64
+	EffectiveGasPrice *decimal.Decimal `gorm:"type:numeric(78,0)"`
+	// FinalizedAt is set once the block is deep enough, also for reverted transactions
+	FinalizedAt *time.Time
+
+	BatchID    *uint64
+	ScheduleID *uint64
+	// IdempotencyKey is the key the transaction was submitted with, it is completed
+	// together with the row
+	IdempotencyKey *string
+
+	// RawTx is the signed transaction, stored before it is broadcast
+	RawTx             *string
+	BroadcastAttempts int
+	LastError         *string
+
+	// LastSeenAt is the last time the node still knew the pending transaction
+	LastSeenAt *time.Time
+	DropReason *string
+
+	// business context given by the caller
+	Memo      *string
+	Labels    pq.StringArray `gorm:"type:text[];default:'{}'"`
+	Reference *string
+
+	// ExpectedAmount of ExpectedToken the sender receives from a pool operation,
+	// previewed at submission
+	ExpectedAmount *decimal.Decimal `gorm:"type:numeric(78,18)"`
+	ExpectedToken  *Token
+
+	// LogIndex is the Transfer event of an external token transfer, a transaction
+	// moving several tokens is stored once per event. It is nil for other rows.
+	LogIndex *uint
+}
+
+// Receipt holds the fields of a transaction receipt stored with the transaction.
+type Receipt struct {
+	BlockNumber       uint64
+	BlockHash         string
+	GasUsed           uint64
+	EffectiveGasPrice *decimal.Decimal
 }
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// WatchedAddress is an address whose incoming transfers are recorded.
+type WatchedAddress struct {
+	Address   string `gorm:"primaryKey"`
+	Label     *string
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+// BlockCursor is the last block processed by a block follower.
+type BlockCursor struct {
+	Name      string `gorm:"primaryKey"`
+	LastBlock uint64
+	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+}
@@ -0,0 +1,182 @@
+package importer
+
+import (
+	"app/internal/blockchain"
+	"app/internal/database"
+	"app/internal/database/models"
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"strings"
+)
+
+// chunkSize is the number of blocks scanned between two checkpoints. It stays
+// below the eth_getLogs range limit of Lotus nodes.
+const chunkSize = 1000
+
+var (
+	ErrNoStartBlock = errors.New("from block is required for the first import of an address")
+	ErrInvalidRange = errors.New("from block is after to block")
+)
+
+// Importer backfills the FIL and iFIL transfers of an address from the chain into
+// the transactions table, marked as external. Progress is checkpointed per address.
+type Importer struct {
+	logger    *zap.Logger
+	bc        blockchain.Client
+	db        database.Database
+	ifil      *common.Address
+	chunkSize uint64
+}
+
+// New creates an importer. Without an iFIL token address only FIL transfers are imported.
+func New(logger *zap.Logger, bc blockchain.Client, db database.Database, ifil *common.Address) *Importer {
+	return &Importer{
+		logger:    logger,
+		bc:        bc,
+		db:        db,
+		ifil:      ifil,
+		chunkSize: chunkSize,
+	}
+}
+
+type Request struct {
+	Address common.Address
+	// FromBlock defaults to the block after the checkpoint of Address
+	FromBlock *uint64
+	// ToBlock defaults to the chain head
+	ToBlock *uint64
+	// MaxBlocks limits the number of scanned blocks, 0 means no limit
+	MaxBlocks uint64
+}
+
+type Result struct {
+	FromBlock uint64
+	ToBlock   uint64
+	Imported  int64
+	// Complete is false if MaxBlocks stopped the import before the requested end
+	Complete bool
+}
+
+func (i *Importer) Import(ctx context.Context, req Request) (*Result, error) {
+	address := strings.ToLower(req.Address.Hex())
+
+	var start uint64
+	switch {
+	case req.FromBlock != nil:
+		start = *req.FromBlock
+	default:
+		checkpoint, err := i.db.GetImportCheckpoint(ctx, address)
+		if err != nil {
+			if errors.Is(err, database.ErrNoCheckpoint) {
+				return nil, ErrNoStartBlock
+			}
+			return nil, errors.Wrap(err, "failed to get import checkpoint")
+		}
+		start = checkpoint.LastBlock + 1
+	}
+
+	var end uint64
+	if req.ToBlock != nil {
+		end = *req.ToBlock
+		if start > end {
+			return nil, ErrInvalidRange
+		}
+	} else {
+		head, err := i.bc.BlockNumber(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get chain head")
+		}
+		end = head
+	}
+
+	result := &Result{FromBlock: start, ToBlock: end, Complete: true}
+	// resumed past the head, nothing to do yet
+	if start > end {
+		return result, nil
+	}
+	if req.MaxBlocks > 0 && end-start+1 > req.MaxBlocks {
+		end = start + req.MaxBlocks - 1
+		result.ToBlock = end
+		result.Complete = false
+	}
+
+	for from := start; from <= end; from += i.chunkSize {
+		to := min(from+i.chunkSize-1, end)
+
+		txs, err := i.scan(ctx, req.Address, from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		imported, err := i.db.ImportTransactions(ctx, address, txs, to)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to store imported transactions")
+		}
+		result.Imported += imported
+
+		i.logger.Info("Imported chain transfers", zap.String("address", address), zap.Uint64("from_block", from), zap.Uint64("to_block", to), zap.Int64("imported", imported))
+	}
+	return result, nil
+}
+
+func (i *Importer) scan(ctx context.Context, address common.Address, from, to uint64) ([]models.Transaction, error) {
+	filter := blockchain.TransferFilter{From: []common.Address{address}, To: []common.Address{address}}
+	transfers, err := i.bc.FindFILTransfers(ctx, filter, from, to)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find FIL transfers")
+	}
+
+	txs := make([]models.Transaction, 0, len(transfers))
+	for _, transfer := range transfers {
+		txs = append(txs, ExternalTransaction(transfer, models.TokenFIL, direction(transfer, address)))
+	}
+
+	if i.ifil == nil {
+		return txs, nil
+	}
+
+	transfers, err = i.bc.FindTokenTransfers(ctx, *i.ifil, filter, from, to)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find iFIL transfers")
+	}
+	for _, transfer := range transfers {
+		txs = append(txs, ExternalTransaction(transfer, models.TokenIFIL, direction(transfer, address)))
+	}
+	return txs, nil
+}
+
+func direction(transfer blockchain.Transfer, address common.Address) models.TransactionDirection {
+	if transfer.To == address && transfer.From != address {
+		return models.DirectionIncoming
+	}
+	return models.DirectionOutgoing
+}
+
+// ExternalTransaction stores a mined transfer as confirmed, the tracker finalizes it.
+func ExternalTransaction(transfer blockchain.Transfer, token models.Token, direction models.TransactionDirection) models.Transaction {
+	status := models.StatusConfirmed
+	if transfer.Reverted {
+		status = models.StatusFailed
+	}
+	blockNumber := transfer.BlockNumber
+	blockHash := transfer.BlockHash.Hex()
+
+	return models.Transaction{
+		Hash:        transfer.Hash.Hex(),
+		Sender:      strings.ToLower(transfer.From.Hex()),
+		Receiver:    strings.ToLower(transfer.To.Hex()),
+		Amount:      decimal.NewFromBigInt(transfer.Amount, 0),
+		Timestamp:   transfer.Time,
+		Status:      status,
+		Token:       token,
+		Kind:        models.KindTransfer,
+		Source:      models.SourceExternal,
+		Direction:   direction,
+		BlockNumber: &blockNumber,
+		BlockHash:   &blockHash,
+		LogIndex:    transfer.LogIndex,
+	}
+}
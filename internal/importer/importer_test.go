@@ -0,0 +1,150 @@
+package importer
+
+import (
+	"app/internal/blockchain"
+	blockchainmock "app/internal/blockchain/mock"
+	"app/internal/database"
+	dbmock "app/internal/database/mock"
+	"app/internal/database/models"
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestImporter_ResumesFromCheckpoint(t *testing.T) {
+	const (
+		account   = "0x5d5d4d04b70bfe49ad7aac8c4454536070daf180"
+		other     = "0xa512eb36e162bfb0e9f55b56bc2a070ca0d3ecd7"
+		ifil      = "0x690908f7fa93afc040cfbd9fe1ddd2c2668aa0e0"
+		filHash   = "0x4033cf2e690e8f6078ab9e665be681c1a9d7c79b6a75d0668da5336fabf43b46"
+		ifilHash  = "0x15e54e2f6e60be523a4ef44e3dc3ab7245bdc98d8b007bfcf1628a320983384b"
+		blockHash = "0x1a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f809"
+	)
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	address := common.HexToAddress(account)
+	filter := blockchain.TransferFilter{From: []common.Address{address}, To: []common.Address{address}}
+	token := common.HexToAddress(ifil)
+	blockTime := time.Date(2025, 4, 13, 12, 0, 0, 0, time.UTC)
+	firstLog, secondLog := uint(2), uint(5)
+
+	mockDatabase.EXPECT().GetImportCheckpoint(gomock.Any(), account).Return(&models.ImportCheckpoint{Address: account, LastBlock: 99}, nil)
+	mockClient.EXPECT().BlockNumber(gomock.Any()).Return(uint64(124), nil)
+
+	// blocks 100..124 in chunks of 10, the last one stops at the head
+	mockClient.EXPECT().
+		FindFILTransfers(gomock.Any(), filter, uint64(100), uint64(109)).
+		Return([]blockchain.Transfer{{
+			Hash:        common.HexToHash(filHash),
+			From:        common.HexToAddress(other),
+			To:          address,
+			Amount:      big.NewInt(5000),
+			BlockNumber: 104,
+			BlockHash:   common.HexToHash(blockHash),
+			Time:        blockTime,
+		}}, nil)
+	mockClient.EXPECT().FindTokenTransfers(gomock.Any(), token, filter, uint64(100), uint64(109)).Return(nil, nil)
+	mockDatabase.EXPECT().
+		ImportTransactions(gomock.Any(), account, gomock.Any(), uint64(109)).
+		DoAndReturn(func(_ context.Context, _ string, txs []models.Transaction, _ uint64) (int64, error) {
+			require.Len(t, txs, 1)
+			require.Equal(t, filHash, txs[0].Hash)
+			require.Equal(t, other, txs[0].Sender)
+			require.Equal(t, account, txs[0].Receiver)
+			require.Equal(t, "5000", txs[0].Amount.String())
+			require.Equal(t, blockTime, txs[0].Timestamp)
+			require.Equal(t, models.StatusConfirmed, txs[0].Status)
+			require.Equal(t, models.TokenFIL, txs[0].Token)
+			require.Equal(t, models.SourceExternal, txs[0].Source)
+			require.Equal(t, models.DirectionIncoming, txs[0].Direction)
+			require.Equal(t, uint64(104), *txs[0].BlockNumber)
+			require.Equal(t, blockHash, *txs[0].BlockHash)
+			return 1, nil
+		})
+
+	mockClient.EXPECT().FindFILTransfers(gomock.Any(), filter, uint64(110), uint64(119)).Return(nil, nil)
+	mockClient.EXPECT().
+		FindTokenTransfers(gomock.Any(), token, filter, uint64(110), uint64(119)).
+		Return([]blockchain.Transfer{{
+			Hash:        common.HexToHash(ifilHash),
+			From:        address,
+			To:          common.HexToAddress(other),
+			Amount:      big.NewInt(7000),
+			BlockNumber: 115,
+			BlockHash:   common.HexToHash(blockHash),
+			Time:        blockTime,
+			LogIndex:    &firstLog,
+		}, {
+			Hash:        common.HexToHash(ifilHash),
+			From:        common.HexToAddress(other),
+			To:          address,
+			Amount:      big.NewInt(2000),
+			BlockNumber: 115,
+			BlockHash:   common.HexToHash(blockHash),
+			Time:        blockTime,
+			LogIndex:    &secondLog,
+		}}, nil)
+	mockDatabase.EXPECT().
+		ImportTransactions(gomock.Any(), account, gomock.Any(), uint64(119)).
+		DoAndReturn(func(_ context.Context, _ string, txs []models.Transaction, _ uint64) (int64, error) {
+			// both Transfer events of the transaction are kept
+			require.Len(t, txs, 2)
+			require.Equal(t, ifilHash, txs[0].Hash)
+			require.Equal(t, models.TokenIFIL, txs[0].Token)
+			require.Equal(t, models.DirectionOutgoing, txs[0].Direction)
+			require.Equal(t, firstLog, *txs[0].LogIndex)
+			require.Equal(t, ifilHash, txs[1].Hash)
+			require.Equal(t, models.DirectionIncoming, txs[1].Direction)
+			require.Equal(t, secondLog, *txs[1].LogIndex)
+			// already stored, e.g. because it was sent through the service
+			return 0, nil
+		})
+
+	mockClient.EXPECT().FindFILTransfers(gomock.Any(), filter, uint64(120), uint64(124)).Return(nil, nil)
+	mockClient.EXPECT().FindTokenTransfers(gomock.Any(), token, filter, uint64(120), uint64(124)).Return(nil, nil)
+	mockDatabase.EXPECT().ImportTransactions(gomock.Any(), account, gomock.Len(0), uint64(124)).Return(int64(0), nil)
+
+	imp := New(zap.NewNop(), mockClient, mockDatabase, &token)
+	imp.chunkSize = 10
+
+	result, err := imp.Import(ctx, Request{Address: address})
+	require.NoError(t, err)
+	require.Equal(t, &Result{FromBlock: 100, ToBlock: 124, Imported: 1, Complete: true}, result)
+}
+
+func TestImporter_MaxBlocks(t *testing.T) {
+	const account = "0x5d5d4d04b70bfe49ad7aac8c4454536070daf180"
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	address := common.HexToAddress(account)
+	filter := blockchain.TransferFilter{From: []common.Address{address}, To: []common.Address{address}}
+	mockClient.EXPECT().FindFILTransfers(gomock.Any(), filter, uint64(500), uint64(504)).Return(nil, nil)
+	mockDatabase.EXPECT().ImportTransactions(gomock.Any(), account, gomock.Any(), uint64(504)).Return(int64(0), nil)
+
+	imp := New(zap.NewNop(), mockClient, mockDatabase, nil)
+
+	from, to := uint64(500), uint64(900)
+	result, err := imp.Import(ctx, Request{Address: address, FromBlock: &from, ToBlock: &to, MaxBlocks: 5})
+	require.NoError(t, err)
+	require.Equal(t, &Result{FromBlock: 500, ToBlock: 504, Complete: false}, result)
+
+	// the first import of an address needs a start block
+	mockDatabase.EXPECT().GetImportCheckpoint(gomock.Any(), account).Return(nil, database.ErrNoCheckpoint)
+	_, err = imp.Import(ctx, Request{Address: address})
+	require.ErrorIs(t, err, ErrNoStartBlock)
+}
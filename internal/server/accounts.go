@@ -0,0 +1,100 @@
+package server
+
+import (
+	"app/internal/wallet"
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+)
+
+func (s *Server) listAccounts(c echo.Context) error {
+	if s.wallet == nil {
+		return ErrWalletDisabled
+	}
+
+	addresses, err := s.wallet.Accounts()
+	if err != nil {
+		s.logger.Error("Failed to list accounts", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to list accounts"))
+	}
+
+	accounts := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		accounts = append(accounts, address.Hex())
+	}
+
+	s.logger.Info("Accounts retrieved", zap.Int("count", len(accounts)))
+	return c.JSON(http.StatusOK, &AccountsResponse{Accounts: accounts})
+}
+
+func (s *Server) createAccount(c echo.Context) error {
+	if s.wallet == nil {
+		return ErrWalletDisabled
+	}
+
+	address, err := s.wallet.NewAccount()
+	if err != nil {
+		s.logger.Error("Failed to create account", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to create account"))
+	}
+
+	s.logger.Info("Account created", zap.String("address", address.Hex()))
+	return c.JSON(http.StatusCreated, &AccountResponse{Address: address.Hex()})
+}
+
+func (s *Server) importAccount(c echo.Context) error {
+	if s.wallet == nil {
+		return ErrWalletDisabled
+	}
+
+	var req ImportAccountRequest
+	if err := c.Bind(&req); err != nil {
+		s.logger.Warn("Invalid request body", zap.Error(err))
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request format")
+	}
+
+	var (
+		address common.Address
+		err     error
+	)
+	switch {
+	case req.PrivateKeyHex != "":
+		privateKey, keyErr := crypto.HexToECDSA(strings.TrimPrefix(req.PrivateKeyHex, "0x"))
+		if keyErr != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, errors.Wrap(keyErr, "invalid private key"))
+		}
+		address, err = s.wallet.ImportKey(privateKey)
+	case len(req.KeystoreJSON) > 0:
+		address, err = s.wallet.ImportJSON(keystoreJSON(req.KeystoreJSON), req.Passphrase)
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "either private_key_hex or keystore_json must be provided")
+	}
+
+	if err != nil {
+		if errors.Is(err, wallet.ErrAccountExists) {
+			return ErrAccountExists
+		}
+		if errors.Is(err, wallet.ErrUnsupported) {
+			return echo.NewHTTPError(http.StatusNotImplemented, err.Error())
+		}
+		s.logger.Error("Failed to import account", zap.Error(err))
+		return echo.NewHTTPError(http.StatusBadRequest, errors.Wrap(err, "failed to import account"))
+	}
+
+	s.logger.Info("Account imported", zap.String("address", address.Hex()))
+	return c.JSON(http.StatusCreated, &AccountResponse{Address: address.Hex()})
+}
+
+// keystoreJSON accepts the key file either as a JSON object or as a JSON-encoded string.
+func keystoreJSON(raw json.RawMessage) []byte {
+	var encoded string
+	if err := json.Unmarshal(raw, &encoded); err == nil {
+		return []byte(encoded)
+	}
+	return raw
+}
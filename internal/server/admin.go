@@ -0,0 +1,58 @@
+package server
+
+import (
+	"app/internal/importer"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+)
+
+// maxImportBlocks keeps a single import request short, larger ranges are imported
+// by repeating the request or with the importer command.
+const maxImportBlocks = 2000
+
+// importHistory backfills the chain transfers of an address, see importer.Importer.
+func (s *Server) importHistory(c echo.Context) error {
+	if s.importer == nil {
+		return ErrImportDisabled
+	}
+
+	var req ImportRequest
+	if err := c.Bind(&req); err != nil {
+		s.logger.Warn("Invalid request body", zap.Error(err))
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request format")
+	}
+	if !isValidAddress(req.Address) {
+		return ErrInvalidAddress
+	}
+
+	address := common.HexToAddress(strings.TrimSpace(req.Address))
+	result, err := s.importer.Import(c.Request().Context(), importer.Request{
+		Address:   address,
+		FromBlock: req.FromBlock,
+		ToBlock:   req.ToBlock,
+		MaxBlocks: maxImportBlocks,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, importer.ErrNoStartBlock):
+			return ErrMissingImportStart
+		case errors.Is(err, importer.ErrInvalidRange):
+			return ErrInvalidImportRange
+		}
+		s.logger.Error("Failed to import chain history", zap.String("address", address.Hex()), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to import chain history"))
+	}
+
+	s.logger.Info("Chain history imported", zap.String("address", address.Hex()), zap.Uint64("to_block", result.ToBlock), zap.Int64("imported", result.Imported))
+	return c.JSON(http.StatusOK, ImportResponse{
+		Address:   strings.ToLower(address.Hex()),
+		FromBlock: result.FromBlock,
+		ToBlock:   result.ToBlock,
+		Imported:  result.Imported,
+		Complete:  result.Complete,
+	})
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"app/internal/database"
+	"app/internal/database/models"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const maxBatchSize = 500
+
+func (s *Server) submitBatch(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req SubmitBatchRequest
+	if err := c.Bind(&req); err != nil {
+		s.logger.Warn("Invalid request body", zap.Error(err))
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request format")
+	}
+
+	token, err := s.resolveToken(ctx, req.Token)
+	if err != nil {
+		return err
+	}
+
+	fees, err := parseFeeParams(req.FeeParams)
+	if err != nil {
+		s.logger.Warn("Invalid fee parameters", zap.Error(err))
+		return err
+	}
+
+	switch {
+	case len(req.Transfers) == 0:
+		return ErrEmptyBatch
+	case len(req.Transfers) > maxBatchSize:
+		return ErrBatchTooLarge
+	}
+
+	// nothing is signed unless every transfer is valid
+	amounts := make([]*big.Int, len(req.Transfers))
+	for i, transfer := range req.Transfers {
+		if !isValidAddress(transfer.Receiver) {
+			return batchItemError(i, ErrInvalidReceiverAddress)
+		}
+		if amounts[i], err = parseAmount(transfer.Amount); err != nil {
+			return batchItemError(i, err)
+		}
+	}
+
+	signer, err := s.resolveSigner(req.From, req.PrivateKeyHex)
+	if err != nil {
+		return err
+	}
+	sender := signer.Address().Hex()
+
+	batch := &models.Batch{Sender: strings.ToLower(sender), Token: token.symbol, TokenAddress: token.address()}
+	if err := s.db.CreateBatch(ctx, batch); err != nil {
+		s.logger.Error("Failed to create batch", zap.String("sender", sender), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to create batch"))
+	}
+
+	// transfers are signed one after another, so they get sequential nonces
+	items := make([]*BatchItemResponse, len(req.Transfers))
+	failed := 0
+	for i, transfer := range req.Transfers {
+		items[i] = &BatchItemResponse{Receiver: transfer.Receiver, Amount: amounts[i].String()}
+
+		signedTx, err := s.submitTransfer(ctx, signer, transfer.Receiver, amounts[i], token, fees, transferMeta{batchID: &batch.ID})
+		if err != nil {
+			s.logger.Error("Failed to submit batch transfer", zap.Uint64("batch_id", batch.ID), zap.Int("index", i), zap.Error(err))
+			items[i].Error = errorMessage(chainError(err, "failed to submit transaction"))
+			failed++
+			continue
+		}
+		items[i].Hash = signedTx.Hash().String()
+		items[i].Status = models.StatusQueued
+	}
+
+	s.logger.Info("Batch queued", zap.Uint64("batch_id", batch.ID), zap.String("sender", sender), zap.Int("transfers", len(items)), zap.Int("failed", failed))
+	return c.JSON(http.StatusCreated, SubmitBatchResponse{BatchID: batch.ID, Items: items})
+}
+
+func (s *Server) getBatch(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return ErrBatchNotFound
+	}
+
+	batch, err := s.db.GetBatch(ctx, id)
+	if err != nil {
+		if errors.Is(err, database.ErrBatchNotFound) {
+			return ErrBatchNotFound
+		}
+		s.logger.Error("Failed to get batch", zap.Uint64("batch_id", id), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to get batch"))
+	}
+
+	txs, err := s.db.GetBatchTransactions(ctx, id)
+	if err != nil {
+		s.logger.Error("Failed to get batch transactions", zap.Uint64("batch_id", id), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to get batch transactions"))
+	}
+
+	response := &BatchResponse{
+		ID:       batch.ID,
+		Sender:   batch.Sender,
+		Token:    batch.Token,
+		Statuses: make(map[models.TransactionStatus]int),
+		Items:    make([]*BatchItemResponse, 0, len(txs)),
+	}
+
+	// a sped up transfer is reported once, by the latest attempt which was not replaced
+	transfers := make(map[string]int, len(txs))
+	for _, tx := range txs {
+		item := &BatchItemResponse{
+			Receiver: tx.Receiver,
+			Amount:   tx.Amount.String(),
+			Hash:     tx.Hash,
+			Status:   tx.Status,
+		}
+
+		root := tx.Hash
+		if tx.Replaces != nil {
+			root = *tx.Replaces
+		}
+		if i, ok := transfers[root]; ok {
+			if tx.Status != models.StatusReplaced {
+				response.Items[i] = item
+			}
+			continue
+		}
+		transfers[root] = len(response.Items)
+		response.Items = append(response.Items, item)
+	}
+
+	response.Total = len(response.Items)
+	for _, item := range response.Items {
+		response.Statuses[item.Status]++
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+func batchItemError(index int, err error) error {
+	return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("transfers[%d]: %s", index, errorMessage(err)))
+}
+
+func errorMessage(err error) string {
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		if inner, ok := httpErr.Message.(error); ok {
+			return inner.Error()
+		}
+		return fmt.Sprint(httpErr.Message)
+	}
+	return err.Error()
+}
@@ -1,16 +1,267 @@
 package server
 
+import (
+	"app/internal/database/models"
+	"encoding/json"
+	"time"
+)
+
 type BalanceResponse struct {
-	FIL  string `json:"fil"`
-	IFIL string `json:"ifil"`
+	FIL    string                 `json:"fil"`
+	IFIL   string                 `json:"ifil"`
+	Tokens []TokenBalanceResponse `json:"tokens"`
 }
 
 type SubmitTransactionRequest struct {
+	From          string `json:"from"`
 	PrivateKeyHex string `json:"private_key_hex"`
 	Receiver      string `json:"receiver"`
 	Amount        string `json:"amount"`
+	Token         string `json:"token"`
+	FeeParams
+
+	Memo      string   `json:"memo"`
+	Labels    []string `json:"labels"`
+	Reference string   `json:"reference"`
+}
+
+// FeeParams selects the fees of a transaction: a speed tier, optionally overridden by explicit caps in attoFIL.
+type FeeParams struct {
+	Speed                string `json:"speed"`
+	MaxFeePerGas         string `json:"max_fee_per_gas"`
+	MaxPriorityFeePerGas string `json:"max_priority_fee_per_gas"`
+}
+
+type SubmitRawTransactionRequest struct {
+	RawTx string `json:"raw_tx"`
 }
 
 type SubmitTransactionResponse struct {
 	Hash string `json:"hash"`
 }
+
+type PrepareTransactionRequest struct {
+	Sender   string `json:"sender"`
+	Receiver string `json:"receiver"`
+	Amount   string `json:"amount"`
+	FeeParams
+}
+
+type PrepareTransactionResponse struct {
+	Type                 uint8  `json:"type"`
+	ChainId              string `json:"chain_id"`
+	Nonce                uint64 `json:"nonce"`
+	To                   string `json:"to"`
+	Value                string `json:"value"`
+	Gas                  uint64 `json:"gas"`
+	MaxFeePerGas         string `json:"max_fee_per_gas"`
+	MaxPriorityFeePerGas string `json:"max_priority_fee_per_gas"`
+	SigningHash          string `json:"signing_hash"`
+	UnsignedTx           string `json:"unsigned_tx"`
+}
+
+type AccountResponse struct {
+	Address string `json:"address"`
+}
+
+type AccountsResponse struct {
+	Accounts []string `json:"accounts"`
+}
+
+type ImportAccountRequest struct {
+	PrivateKeyHex string          `json:"private_key_hex"`
+	KeystoreJSON  json.RawMessage `json:"keystore_json"`
+	Passphrase    string          `json:"passphrase"`
+}
+
+type FeeSuggestionResponse struct {
+	MaxPriorityFeePerGas string `json:"max_priority_fee_per_gas"`
+	MaxFeePerGas         string `json:"max_fee_per_gas"`
+	EstimatedCost        string `json:"estimated_cost"`
+	MaxCost              string `json:"max_cost"`
+}
+
+type FeesResponse struct {
+	BaseFee  string                 `json:"base_fee"`
+	GasLimit uint64                 `json:"gas_limit"`
+	Slow     *FeeSuggestionResponse `json:"slow"`
+	Standard *FeeSuggestionResponse `json:"standard"`
+	Fast     *FeeSuggestionResponse `json:"fast"`
+}
+
+type ReplaceTransactionRequest struct {
+	From          string `json:"from"`
+	PrivateKeyHex string `json:"private_key_hex"`
+	FeeParams
+}
+
+type ReplaceTransactionResponse struct {
+	Hash     string `json:"hash"`
+	Replaces string `json:"replaces"`
+}
+
+type TransactionResponse struct {
+	Hash   string                    `json:"hash"`
+	Stored *models.Transaction       `json:"stored"`
+	Chain  *ChainTransactionResponse `json:"chain"`
+}
+
+type ChainTransactionResponse struct {
+	From          string  `json:"from"`
+	To            string  `json:"to"`
+	Value         string  `json:"value"`
+	Nonce         uint64  `json:"nonce"`
+	Status        string  `json:"status"`
+	BlockNumber   *uint64 `json:"block_number,omitempty"`
+	BlockHash     string  `json:"block_hash,omitempty"`
+	Confirmations uint64  `json:"confirmations"`
+	GasUsed       uint64  `json:"gas_used,omitempty"`
+	GasPrice      string  `json:"effective_gas_price,omitempty"`
+	FeePaid       string  `json:"fee_paid,omitempty"`
+}
+
+type BatchTransferRequest struct {
+	Receiver string `json:"receiver"`
+	Amount   string `json:"amount"`
+}
+
+type SubmitBatchRequest struct {
+	From          string                 `json:"from"`
+	PrivateKeyHex string                 `json:"private_key_hex"`
+	Token         string                 `json:"token"`
+	Transfers     []BatchTransferRequest `json:"transfers"`
+	FeeParams
+}
+
+type BatchItemResponse struct {
+	Receiver string                   `json:"receiver"`
+	Amount   string                   `json:"amount"`
+	Hash     string                   `json:"hash,omitempty"`
+	Status   models.TransactionStatus `json:"status,omitempty"`
+	Error    string                   `json:"error,omitempty"`
+}
+
+type SubmitBatchResponse struct {
+	BatchID uint64               `json:"batch_id"`
+	Items   []*BatchItemResponse `json:"items"`
+}
+
+type BatchResponse struct {
+	ID       uint64                           `json:"id"`
+	Sender   string                           `json:"sender"`
+	Token    models.Token                     `json:"token"`
+	Total    int                              `json:"total"`
+	Statuses map[models.TransactionStatus]int `json:"statuses"`
+	Items    []*BatchItemResponse             `json:"items"`
+}
+
+type ScheduleRequest struct {
+	From     string     `json:"from"`
+	Receiver string     `json:"receiver"`
+	Amount   string     `json:"amount"`
+	Token    string     `json:"token"`
+	Speed    string     `json:"speed"`
+	RunAt    *time.Time `json:"run_at"`
+	Cron     string     `json:"cron"`
+}
+
+type ScheduleResponse struct {
+	ID         uint64                `json:"id"`
+	From       string                `json:"from"`
+	Receiver   string                `json:"receiver"`
+	Amount     string                `json:"amount"`
+	Token      models.Token          `json:"token"`
+	Speed      string                `json:"speed,omitempty"`
+	Cron       *string               `json:"cron,omitempty"`
+	NextRunAt  *time.Time            `json:"next_run_at"`
+	Status     models.ScheduleStatus `json:"status"`
+	LastRunAt  *time.Time            `json:"last_run_at,omitempty"`
+	LastTxHash *string               `json:"last_tx_hash,omitempty"`
+	LastError  *string               `json:"last_error,omitempty"`
+	CreatedAt  time.Time             `json:"created_at"`
+}
+
+type ImportRequest struct {
+	Address   string  `json:"address"`
+	FromBlock *uint64 `json:"from_block"`
+	ToBlock   *uint64 `json:"to_block"`
+}
+
+type ImportResponse struct {
+	Address   string `json:"address"`
+	FromBlock uint64 `json:"from_block"`
+	ToBlock   uint64 `json:"to_block"`
+	Imported  int64  `json:"imported"`
+	// Complete is false if the range was cut to the per request limit
+	Complete bool `json:"complete"`
+}
+
+type WatchedAddressRequest struct {
+	Address string  `json:"address"`
+	Label   *string `json:"label"`
+}
+
+type WatchedAddressResponse struct {
+	Address   string    `json:"address"`
+	Label     *string   `json:"label,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type TokenRequest struct {
+	Address string `json:"address"`
+	Symbol  string `json:"symbol"`
+	// Decimals is read from the contract if omitted
+	Decimals *uint8 `json:"decimals"`
+}
+
+type TokenResponse struct {
+	Symbol    models.Token `json:"symbol"`
+	Address   string       `json:"address"`
+	Decimals  uint8        `json:"decimals"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type TokenBalanceResponse struct {
+	Symbol  models.Token `json:"symbol"`
+	Address string       `json:"address"`
+	Balance string       `json:"balance,omitempty"`
+	// Error is set instead of Balance if the token contract could not be queried
+	Error string `json:"error,omitempty"`
+}
+
+type PoolDepositRequest struct {
+	From          string `json:"from"`
+	PrivateKeyHex string `json:"private_key_hex"`
+	// Amount of FIL to deposit in attoFIL
+	Amount string `json:"amount"`
+	FeeParams
+}
+
+type PoolDepositResponse struct {
+	Hash   string `json:"hash"`
+	Amount string `json:"amount"`
+	// ExpectedIFIL is the iFIL minted at the exchange rate of submission, in the
+	// smallest iFIL unit
+	ExpectedIFIL string `json:"expected_ifil"`
+}
+
+// PoolWithdrawRequest exchanges iFIL for FIL, either IFILAmount or FILAmount must be given.
+type PoolWithdrawRequest struct {
+	From          string `json:"from"`
+	PrivateKeyHex string `json:"private_key_hex"`
+	// IFILAmount redeems exactly this much iFIL, in the smallest iFIL unit
+	IFILAmount string `json:"ifil_amount"`
+	// FILAmount withdraws exactly this much FIL, in attoFIL
+	FILAmount string `json:"fil_amount"`
+	FeeParams
+}
+
+type PoolWithdrawResponse struct {
+	// Hash is empty if the withdrawal has to wait for ApprovalHash to be mined
+	Hash string `json:"hash,omitempty"`
+	// ApprovalHash is set if the pool may not burn the iFIL yet
+	ApprovalHash *string `json:"approval_hash,omitempty"`
+	// IFIL burned and ExpectedFIL paid out at the exchange rate of submission
+	IFIL        string `json:"ifil"`
+	ExpectedFIL string `json:"expected_fil"`
+}
@@ -10,4 +10,51 @@ var (
 	ErrInvalidSenderAddress   = echo.NewHTTPError(http.StatusBadRequest, "invalid sender address")
 	ErrInvalidReceiverAddress = echo.NewHTTPError(http.StatusBadRequest, "invalid receiver address")
 	ErrInvalidTxAmount        = echo.NewHTTPError(http.StatusBadRequest, "invalid tx amount: must be positive value")
+	ErrInvalidToken           = echo.NewHTTPError(http.StatusBadRequest, "invalid token: must be FIL, iFIL or a registered token")
+	ErrInvalidFeeTier         = echo.NewHTTPError(http.StatusBadRequest, "invalid speed: must be slow, standard or fast")
+	ErrInvalidFeeCap          = echo.NewHTTPError(http.StatusBadRequest, "invalid fee cap: must be positive value")
+	ErrInvalidFeeCaps         = echo.NewHTTPError(http.StatusBadRequest, "max_priority_fee_per_gas must not exceed max_fee_per_gas")
+	ErrInvalidRawTx           = echo.NewHTTPError(http.StatusBadRequest, "invalid raw transaction")
+	ErrInvalidTxHash          = echo.NewHTTPError(http.StatusBadRequest, "invalid transaction hash")
+	ErrChainIdMismatch        = echo.NewHTTPError(http.StatusBadRequest, "transaction chain id does not match configured chain")
+	ErrInvalidTxSignature     = echo.NewHTTPError(http.StatusBadRequest, "invalid transaction signature")
+	ErrMissingTxReceiver      = echo.NewHTTPError(http.StatusBadRequest, "contract creation transactions are not supported")
+	ErrMissingSigner          = echo.NewHTTPError(http.StatusBadRequest, "either from or private_key_hex must be provided")
+	ErrUnknownAccount         = echo.NewHTTPError(http.StatusBadRequest, "from is not a managed account")
+	ErrAccountExists          = echo.NewHTTPError(http.StatusConflict, "account already exists")
+	ErrWalletDisabled         = echo.NewHTTPError(http.StatusServiceUnavailable, "wallet is not configured")
+	ErrInvalidPoolExit        = echo.NewHTTPError(http.StatusBadRequest, "exactly one of ifil_amount and fil_amount must be provided")
+	ErrImportDisabled         = echo.NewHTTPError(http.StatusServiceUnavailable, "importer is not configured")
+	ErrMissingImportStart     = echo.NewHTTPError(http.StatusBadRequest, "from_block is required for the first import of an address")
+	ErrInvalidImportRange     = echo.NewHTTPError(http.StatusBadRequest, "from_block must not be after to_block")
+	ErrSignerRejected         = echo.NewHTTPError(http.StatusForbidden, "transaction rejected by signer")
+	ErrSignerTimeout          = echo.NewHTTPError(http.StatusGatewayTimeout, "signer did not respond in time")
+	ErrSignerMismatch         = echo.NewHTTPError(http.StatusBadRequest, "signer is not the sender of the transaction")
+	ErrTxNotFound             = echo.NewHTTPError(http.StatusNotFound, "transaction not found")
+	ErrEmptyBatch             = echo.NewHTTPError(http.StatusBadRequest, "batch has no transfers")
+	ErrBatchTooLarge          = echo.NewHTTPError(http.StatusBadRequest, "batch has too many transfers")
+	ErrBatchNotFound          = echo.NewHTTPError(http.StatusNotFound, "batch not found")
+	ErrMissingScheduleSender  = echo.NewHTTPError(http.StatusBadRequest, "from must be a managed account")
+	ErrInvalidScheduleTime    = echo.NewHTTPError(http.StatusBadRequest, "exactly one of run_at and cron must be provided")
+	ErrScheduleInPast         = echo.NewHTTPError(http.StatusBadRequest, "run_at must be in the future")
+	ErrScheduleNotFound       = echo.NewHTTPError(http.StatusNotFound, "schedule not found")
+	ErrScheduleCancelled      = echo.NewHTTPError(http.StatusConflict, "schedule is cancelled")
+	ErrTxExists               = echo.NewHTTPError(http.StatusConflict, "transaction was already submitted")
+	ErrTxNotPending           = echo.NewHTTPError(http.StatusConflict, "transaction is not pending")
+	ErrTxBroadcastMeanwhile   = echo.NewHTTPError(http.StatusConflict, "transaction was broadcast meanwhile, retry to replace it")
+	ErrInvalidIdempotencyKey  = echo.NewHTTPError(http.StatusBadRequest, "invalid Idempotency-Key: must be at most 255 characters")
+	ErrIdempotencyKeyReused   = echo.NewHTTPError(http.StatusConflict, "Idempotency-Key was already used with a different request")
+	ErrIdempotencyKeyPending  = echo.NewHTTPError(http.StatusConflict, "request with this Idempotency-Key is still in progress")
+	ErrInvalidMemo            = echo.NewHTTPError(http.StatusBadRequest, "invalid memo: must be at most 1024 characters")
+	ErrInvalidLabels          = echo.NewHTTPError(http.StatusBadRequest, "invalid labels: at most 20 labels of 1 to 64 characters")
+	ErrInvalidReference       = echo.NewHTTPError(http.StatusBadRequest, "invalid reference: must be at most 255 characters")
+	ErrInvalidDirection       = echo.NewHTTPError(http.StatusBadRequest, "invalid direction: must be incoming or outgoing")
+	ErrWatchedAddressExists   = echo.NewHTTPError(http.StatusConflict, "address is already watched")
+	ErrWatchedAddressNotFound = echo.NewHTTPError(http.StatusNotFound, "watched address not found")
+	ErrInvalidTokenSymbol     = echo.NewHTTPError(http.StatusBadRequest, "invalid symbol: 1 to 32 letters, digits, '.', '_' or '-', other than FIL and iFIL")
+	ErrInvalidTokenContract   = echo.NewHTTPError(http.StatusBadRequest, "address is not an ERC-20 token contract")
+	ErrTokenDecimalsMismatch  = echo.NewHTTPError(http.StatusBadRequest, "decimals differ from the decimals of the token contract")
+	ErrTokenExists            = echo.NewHTTPError(http.StatusConflict, "token address or symbol is already registered")
+	ErrTokenNotFound          = echo.NewHTTPError(http.StatusNotFound, "token not found")
+	ErrFeeBumpTooLow          = echo.NewHTTPError(http.StatusBadRequest, "replacement fees must be at least 30% higher than the original ones")
 )
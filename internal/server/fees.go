@@ -0,0 +1,88 @@
+package server
+
+import (
+	"app/internal/blockchain"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"math/big"
+	"net/http"
+	"strings"
+)
+
+func (s *Server) getFees(c echo.Context) error {
+	sender := c.QueryParam("sender")
+	receiver := c.QueryParam("receiver")
+
+	if !isValidAddress(sender) {
+		s.logger.Warn("Invalid sender address", zap.String("sender", sender))
+		return ErrInvalidSenderAddress
+	}
+
+	if !isValidAddress(receiver) {
+		s.logger.Warn("Invalid receiver address", zap.String("receiver", receiver))
+		return ErrInvalidReceiverAddress
+	}
+
+	amount, err := parseAmount(c.QueryParam("amount"))
+	if err != nil {
+		return err
+	}
+
+	fees, err := s.bc.SuggestFees(c.Request().Context(), common.HexToAddress(strings.TrimPrefix(sender, "0x")), common.HexToAddress(strings.TrimPrefix(receiver, "0x")), amount)
+	if err != nil {
+		s.logger.Error("Failed to suggest fees", zap.String("sender", sender), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to suggest fees"))
+	}
+
+	s.logger.Info("Fees suggested", zap.String("sender", sender), zap.String("base_fee", fees.BaseFee.String()))
+	return c.JSON(http.StatusOK, &FeesResponse{
+		BaseFee:  fees.BaseFee.String(),
+		GasLimit: fees.GasLimit,
+		Slow:     newFeeSuggestionResponse(fees.Tiers[blockchain.FeeTierSlow]),
+		Standard: newFeeSuggestionResponse(fees.Tiers[blockchain.FeeTierStandard]),
+		Fast:     newFeeSuggestionResponse(fees.Tiers[blockchain.FeeTierFast]),
+	})
+}
+
+func newFeeSuggestionResponse(fee *blockchain.FeeSuggestion) *FeeSuggestionResponse {
+	return &FeeSuggestionResponse{
+		MaxPriorityFeePerGas: fee.GasTipCap.String(),
+		MaxFeePerGas:         fee.GasFeeCap.String(),
+		EstimatedCost:        fee.EstimatedCost.String(),
+		MaxCost:              fee.MaxCost.String(),
+	}
+}
+
+func parseFeeParams(p FeeParams) (blockchain.FeeOptions, error) {
+	tier, err := blockchain.ParseFeeTier(p.Speed)
+	if err != nil {
+		return blockchain.FeeOptions{}, ErrInvalidFeeTier
+	}
+
+	opts := blockchain.FeeOptions{Tier: tier}
+	if p.MaxFeePerGas != "" {
+		if opts.GasFeeCap, err = parseFeeCap(p.MaxFeePerGas); err != nil {
+			return blockchain.FeeOptions{}, err
+		}
+	}
+	if p.MaxPriorityFeePerGas != "" {
+		if opts.GasTipCap, err = parseFeeCap(p.MaxPriorityFeePerGas); err != nil {
+			return blockchain.FeeOptions{}, err
+		}
+	}
+
+	if opts.GasFeeCap != nil && opts.GasTipCap != nil && opts.GasTipCap.Cmp(opts.GasFeeCap) > 0 {
+		return blockchain.FeeOptions{}, ErrInvalidFeeCaps
+	}
+	return opts, nil
+}
+
+func parseFeeCap(s string) (*big.Int, error) {
+	value, ok := new(big.Int).SetString(s, 10)
+	if !ok || value.Sign() <= 0 {
+		return nil, ErrInvalidFeeCap
+	}
+	return value, nil
+}
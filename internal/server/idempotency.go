@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	idempotencyKeyHeader    = "Idempotency-Key"
+	maxIdempotencyKeyLength = 255
+	// idempotencyKeyTimeout is the time after which a key still in progress is
+	// assumed to be abandoned, e.g. because the service was restarted meanwhile
+	idempotencyKeyTimeout = 10 * time.Minute
+)
+
+// reserveIdempotencyKey claims key for the request of sender. If key was used
+// before with the same request, the response of that request is returned instead.
+func (s *Server) reserveIdempotencyKey(ctx context.Context, key, sender string, req SubmitTransactionRequest) (*SubmitTransactionResponse, error) {
+	if len(key) > maxIdempotencyKeyLength {
+		return nil, ErrInvalidIdempotencyKey
+	}
+
+	fingerprint, err := requestFingerprint(sender, req)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to fingerprint request"))
+	}
+
+	record, reserved, err := s.db.ReserveIdempotencyKey(ctx, key, fingerprint, time.Now().Add(-idempotencyKeyTimeout))
+	if err != nil {
+		s.logger.Error("Failed to reserve idempotency key", zap.String("key", key), zap.Error(err))
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to reserve idempotency key"))
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	switch {
+	case record.Fingerprint != fingerprint:
+		s.logger.Warn("Idempotency key reused with a different request", zap.String("key", key))
+		return nil, ErrIdempotencyKeyReused
+	case record.TxHash == nil:
+		return nil, ErrIdempotencyKeyPending
+	}
+	s.logger.Info("Replaying idempotent request", zap.String("key", key), zap.String("hash", *record.TxHash))
+	return &SubmitTransactionResponse{Hash: *record.TxHash}, nil
+}
+
+// requestFingerprint hashes req with its sender in place of the private key, secrets
+// must not end up in the stored fingerprint.
+func requestFingerprint(sender string, req SubmitTransactionRequest) (string, error) {
+	req.From = strings.ToLower(sender)
+	req.PrivateKeyHex = ""
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (s *Server) releaseIdempotencyKey(ctx context.Context, key string) {
+	if err := s.db.ReleaseIdempotencyKey(ctx, key); err != nil {
+		s.logger.Error("Failed to release idempotency key", zap.String("key", key), zap.Error(err))
+	}
+}
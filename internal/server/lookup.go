@@ -0,0 +1,92 @@
+package server
+
+import (
+	"app/internal/blockchain"
+	"app/internal/database"
+	"app/internal/database/models"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"math/big"
+	"net/http"
+)
+
+// getTransaction returns the stored row of a transaction together with its
+// on-chain state. Either of them may be missing, but not both.
+func (s *Server) getTransaction(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	hash, err := parseTxHash(c.Param("hash"))
+	if err != nil {
+		return err
+	}
+
+	stored, err := s.db.GetTransaction(ctx, hash.String())
+	if err != nil && !errors.Is(err, database.ErrTxNotFound) {
+		s.logger.Error("Failed to get transaction", zap.String("hash", hash.String()), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to get transaction"))
+	}
+
+	info, err := s.bc.GetTransaction(ctx, hash)
+	if err != nil && !errors.Is(err, blockchain.ErrTxNotFound) {
+		if stored == nil {
+			s.logger.Error("Failed to get transaction from chain", zap.String("hash", hash.String()), zap.Error(err))
+			return echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to get transaction from chain"))
+		}
+		// the stored row is still worth returning
+		s.logger.Warn("Failed to get transaction from chain", zap.String("hash", hash.String()), zap.Error(err))
+	}
+
+	if stored == nil && info == nil {
+		return ErrTxNotFound
+	}
+
+	response := &TransactionResponse{Hash: hash.String(), Stored: stored}
+	if info != nil {
+		response.Chain = newChainTransactionResponse(info)
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+func newChainTransactionResponse(info *blockchain.TransactionInfo) *ChainTransactionResponse {
+	response := &ChainTransactionResponse{
+		From:          info.From.Hex(),
+		Value:         info.Tx.Value().String(),
+		Nonce:         info.Tx.Nonce(),
+		Status:        string(models.StatusPending),
+		Confirmations: info.Confirmations,
+	}
+	if to := info.Tx.To(); to != nil {
+		response.To = to.Hex()
+	}
+
+	receipt := info.Receipt
+	if receipt == nil {
+		return response
+	}
+
+	response.Status = string(models.StatusFailed)
+	if receipt.Status == types.ReceiptStatusSuccessful {
+		response.Status = string(models.StatusConfirmed)
+	}
+	blockNumber := receipt.BlockNumber.Uint64()
+	response.BlockNumber = &blockNumber
+	response.BlockHash = receipt.BlockHash.Hex()
+	response.GasUsed = receipt.GasUsed
+	if receipt.EffectiveGasPrice != nil {
+		response.GasPrice = receipt.EffectiveGasPrice.String()
+		response.FeePaid = new(big.Int).Mul(receipt.EffectiveGasPrice, new(big.Int).SetUint64(receipt.GasUsed)).String()
+	}
+	return response
+}
+
+func parseTxHash(s string) (common.Hash, error) {
+	b, err := hexutil.Decode(s)
+	if err != nil || len(b) != common.HashLength {
+		return common.Hash{}, ErrInvalidTxHash
+	}
+	return common.BytesToHash(b), nil
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxMemoLength      = 1024
+	maxLabels          = 20
+	maxLabelLength     = 64
+	maxReferenceLength = 255
+)
+
+// parseTransferMeta validates the memo, labels and reference of a transfer.
+// Labels are trimmed and duplicates dropped, empty values are not stored.
+func parseTransferMeta(memo string, labels []string, reference string) (transferMeta, error) {
+	var meta transferMeta
+
+	if utf8.RuneCountInString(memo) > maxMemoLength {
+		return meta, ErrInvalidMemo
+	}
+	if memo != "" {
+		meta.memo = &memo
+	}
+
+	if len(labels) > maxLabels {
+		return meta, ErrInvalidLabels
+	}
+	seen := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		label = strings.TrimSpace(label)
+		if label == "" || utf8.RuneCountInString(label) > maxLabelLength {
+			return meta, ErrInvalidLabels
+		}
+		if !seen[label] {
+			seen[label] = true
+			meta.labels = append(meta.labels, label)
+		}
+	}
+
+	reference = strings.TrimSpace(reference)
+	if utf8.RuneCountInString(reference) > maxReferenceLength {
+		return meta, ErrInvalidReference
+	}
+	if reference != "" {
+		meta.reference = &reference
+	}
+	return meta, nil
+}
@@ -0,0 +1,198 @@
+package server
+
+import (
+	"app/internal/blockchain"
+	"app/internal/database"
+	"app/internal/database/models"
+	"context"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"math/big"
+	"net/http"
+	"strings"
+)
+
+// depositToPool stakes FIL in the Infinity Pool. The deposit is queued for the
+// broadcaster like a transfer, with the iFIL expected at the current exchange rate.
+func (s *Server) depositToPool(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req PoolDepositRequest
+	if err := c.Bind(&req); err != nil {
+		s.logger.Warn("Invalid request body", zap.Error(err))
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request format")
+	}
+
+	fees, err := parseFeeParams(req.FeeParams)
+	if err != nil {
+		s.logger.Warn("Invalid fee parameters", zap.Error(err))
+		return err
+	}
+
+	signer, err := s.resolveSigner(req.From, req.PrivateKeyHex)
+	if err != nil {
+		return err
+	}
+
+	amount, err := parseAmount(req.Amount)
+	if err != nil {
+		return err
+	}
+
+	expected, err := s.bc.PreviewDeposit(ctx, amount)
+	if err != nil {
+		s.logger.Error("Failed to preview pool deposit", zap.Error(err))
+		return chainError(err, "failed to preview pool deposit")
+	}
+
+	txHash, err := s.queuePoolTransaction(signer, "pool deposit", models.KindDeposit, amount, models.TokenFIL, expected, models.TokenIFIL,
+		func() (*types.Transaction, error) {
+			return s.bc.SignPoolDeposit(ctx, signer, amount, fees)
+		})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, PoolDepositResponse{
+		Hash:         txHash,
+		Amount:       amount.String(),
+		ExpectedIFIL: expected.String(),
+	})
+}
+
+// withdrawFromPool exchanges iFIL for FIL at the Infinity Pool, either redeeming an
+// amount of iFIL or withdrawing an amount of FIL. If the pool may not burn enough
+// iFIL of the sender, only an approval is queued. The withdrawal is estimated against
+// the allowance, so the client requests it again once the approval is mined.
+func (s *Server) withdrawFromPool(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req PoolWithdrawRequest
+	if err := c.Bind(&req); err != nil {
+		s.logger.Warn("Invalid request body", zap.Error(err))
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request format")
+	}
+
+	var exit blockchain.PoolExit
+	var err error
+	switch {
+	case (req.IFILAmount == "") == (req.FILAmount == ""):
+		return ErrInvalidPoolExit
+	case req.IFILAmount != "":
+		exit.Redeem = true
+		exit.Amount, err = parseAmount(req.IFILAmount)
+	default:
+		exit.Amount, err = parseAmount(req.FILAmount)
+	}
+	if err != nil {
+		return err
+	}
+
+	fees, err := parseFeeParams(req.FeeParams)
+	if err != nil {
+		s.logger.Warn("Invalid fee parameters", zap.Error(err))
+		return err
+	}
+
+	signer, err := s.resolveSigner(req.From, req.PrivateKeyHex)
+	if err != nil {
+		return err
+	}
+	sender := signer.Address()
+
+	shares, assets, err := s.bc.PreviewPoolExit(ctx, exit)
+	if err != nil {
+		s.logger.Error("Failed to preview pool withdrawal", zap.Error(err))
+		return chainError(err, "failed to preview pool withdrawal")
+	}
+
+	allowance, err := s.bc.PoolAllowance(ctx, sender)
+	if err != nil {
+		s.logger.Error("Failed to get iFIL allowance", zap.String("sender", sender.Hex()), zap.Error(err))
+		return chainError(err, "failed to get iFIL allowance")
+	}
+
+	response := PoolWithdrawResponse{IFIL: shares.String(), ExpectedFIL: assets.String()}
+
+	if allowance.Cmp(shares) < 0 {
+		approvalHash, err := s.approvePool(ctx, signer, shares, fees)
+		if err != nil {
+			return err
+		}
+		response.ApprovalHash = &approvalHash
+		return c.JSON(http.StatusAccepted, response)
+	}
+
+	response.Hash, err = s.queuePoolTransaction(signer, "pool withdrawal", models.KindWithdrawal, shares, models.TokenIFIL, assets, models.TokenFIL,
+		func() (*types.Transaction, error) {
+			return s.bc.SignPoolExit(ctx, signer, exit, fees)
+		})
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, response)
+}
+
+// approvePool queues an approval for the pool to burn shares iFIL of the signer and
+// returns its hash. An approval queued by an earlier request is returned instead, so
+// retries before it is mined don't queue more of them.
+func (s *Server) approvePool(ctx context.Context, signer blockchain.Signer, shares *big.Int, fees blockchain.FeeOptions) (string, error) {
+	sender := signer.Address()
+
+	existing, err := s.db.GetOpenApproval(ctx, strings.ToLower(sender.Hex()), decimal.NewFromBigInt(shares, 0))
+	switch {
+	case err == nil:
+		s.logger.Info("iFIL approval still open", zap.String("hash", existing.Hash), zap.String("sender", sender.Hex()))
+		return existing.Hash, nil
+	case !errors.Is(err, database.ErrTxNotFound):
+		s.logger.Error("Failed to get open iFIL approval", zap.String("sender", sender.Hex()), zap.Error(err))
+		return "", echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to get open iFIL approval"))
+	}
+
+	return s.queuePoolTransaction(signer, "iFIL approval", models.KindApproval, shares, models.TokenIFIL, nil, "",
+		func() (*types.Transaction, error) {
+			return s.bc.SignPoolApproval(ctx, signer, shares, fees)
+		})
+}
+
+// queuePoolTransaction signs a pool operation of signer with sign and stores it for
+// the broadcaster, returning its hash. amount of token is what the signer spends,
+// expected of expectedToken what it receives.
+func (s *Server) queuePoolTransaction(signer blockchain.Signer, operation string, kind models.TransactionKind, amount *big.Int, token models.Token, expected *big.Int, expectedToken models.Token, sign func() (*types.Transaction, error)) (string, error) {
+	sender := signer.Address()
+	tx, err := sign()
+	if err != nil {
+		s.logger.Error("Failed to sign "+operation, zap.String("sender", sender.Hex()), zap.Error(err))
+		return "", chainError(err, "failed to submit "+operation)
+	}
+
+	row := &models.Transaction{
+		Sender:   strings.ToLower(sender.Hex()),
+		Receiver: strings.ToLower(tx.To().Hex()),
+		Amount:   decimal.NewFromBigInt(amount, 0),
+		Token:    token,
+		Kind:     kind,
+	}
+	if expected != nil {
+		expectedAmount := decimal.NewFromBigInt(expected, 0)
+		row.ExpectedAmount = &expectedAmount
+		row.ExpectedToken = &expectedToken
+	}
+
+	// the transaction is recorded before it can reach the chain, the broadcaster sends it
+	err = setTxDetails(row, tx)
+	if err == nil {
+		err = s.saveTransaction(row)
+	}
+	if err != nil {
+		s.bc.ReleaseNonce(sender, tx.Nonce())
+		return "", chainError(err, "failed to submit "+operation)
+	}
+
+	txHash := tx.Hash().String()
+	s.logger.Info("Queued "+operation, zap.String("hash", txHash), zap.String("sender", sender.Hex()), zap.String("amount", amount.String()))
+	return txHash, nil
+}
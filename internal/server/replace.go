@@ -0,0 +1,161 @@
+package server
+
+import (
+	"app/internal/blockchain"
+	"app/internal/database"
+	"app/internal/database/models"
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+)
+
+func (s *Server) speedUpTransaction(c echo.Context) error {
+	return s.replaceTransaction(c, "speed up", s.bc.SpeedUpTransaction, nil, func(original *models.Transaction) *models.Transaction {
+		// the replacement still carries out the transfer of the batch or schedule run
+		return &models.Transaction{
+			Sender:       original.Sender,
+			Receiver:     original.Receiver,
+			Amount:       original.Amount,
+			Token:        original.Token,
+			TokenAddress: original.TokenAddress,
+			Kind:         original.Kind,
+			BatchID:      original.BatchID,
+			ScheduleID:   original.ScheduleID,
+			Memo:         original.Memo,
+			Labels:       original.Labels,
+			Reference:    original.Reference,
+
+			ExpectedAmount: original.ExpectedAmount,
+			ExpectedToken:  original.ExpectedToken,
+		}
+	})
+}
+
+func (s *Server) cancelTransaction(c echo.Context) error {
+	return s.replaceTransaction(c, "cancel", s.bc.CancelTransaction, s.cancelQueuedTransaction, func(original *models.Transaction) *models.Transaction {
+		// not part of a batch or schedule, the cancelled transfer is reported there instead
+		return &models.Transaction{
+			Sender:   original.Sender,
+			Receiver: original.Sender,
+			Amount:   decimal.Zero,
+			Token:    models.TokenFIL,
+			Kind:     models.KindCancel,
+		}
+	})
+}
+
+type replaceFunc func(ctx context.Context, signer blockchain.Signer, hash common.Hash, fees blockchain.FeeOptions) (*types.Transaction, error)
+
+// replaceTransaction replaces the pending transaction of the :hash path parameter
+// using replace and stores the replacement built by newTx. If queued is set, it
+// handles transactions which are not broadcast yet instead.
+func (s *Server) replaceTransaction(c echo.Context, action string, replace replaceFunc, queued func(echo.Context, *models.Transaction) error, newTx func(*models.Transaction) *models.Transaction) error {
+	ctx := c.Request().Context()
+
+	var req ReplaceTransactionRequest
+	if err := c.Bind(&req); err != nil {
+		s.logger.Warn("Invalid request body", zap.Error(err))
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request format")
+	}
+
+	fees, err := parseFeeParams(req.FeeParams)
+	if err != nil {
+		s.logger.Warn("Invalid fee parameters", zap.Error(err))
+		return err
+	}
+
+	original, err := s.pendingTransaction(c, queued != nil)
+	if err != nil {
+		return err
+	}
+
+	// managed senders don't need to repeat their address
+	from := req.From
+	if from == "" && req.PrivateKeyHex == "" {
+		from = original.Sender
+	}
+	signer, err := s.resolveSigner(from, req.PrivateKeyHex)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(signer.Address().Hex(), original.Sender) {
+		return ErrSignerMismatch
+	}
+	if original.Status == models.StatusQueued {
+		return queued(c, original)
+	}
+
+	tx, err := replace(ctx, signer, common.HexToHash(original.Hash), fees)
+	if err != nil {
+		s.logger.Error("Failed to "+action+" transaction", zap.String("hash", original.Hash), zap.Error(err))
+		return chainError(err, "failed to "+action+" transaction")
+	}
+
+	// all replacements point to the first transaction of the nonce
+	root := original.Hash
+	if original.Replaces != nil {
+		root = *original.Replaces
+	}
+
+	replacement := newTx(original)
+	replacement.Replaces = &root
+	if err := setTxDetails(replacement, tx); err != nil {
+		return chainError(err, "failed to "+action+" transaction")
+	}
+	if err := s.saveTransaction(replacement); err != nil {
+		return err
+	}
+
+	s.logger.Info("Transaction replaced", zap.String("action", action), zap.String("hash", replacement.Hash), zap.String("replaces", original.Hash))
+	return c.JSON(http.StatusCreated, ReplaceTransactionResponse{Hash: replacement.Hash, Replaces: root})
+}
+
+// cancelQueuedTransaction cancels a transaction which never reached the node and
+// releases its nonce for the next transaction of the sender.
+func (s *Server) cancelQueuedTransaction(c echo.Context, tx *models.Transaction) error {
+	if err := s.db.CancelQueuedTransaction(c.Request().Context(), tx.Hash); err != nil {
+		if errors.Is(err, database.ErrTxNotFound) {
+			return ErrTxBroadcastMeanwhile
+		}
+		s.logger.Error("Failed to cancel queued transaction", zap.String("hash", tx.Hash), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to cancel queued transaction"))
+	}
+	if tx.Nonce != nil {
+		s.bc.ReleaseNonce(common.HexToAddress(tx.Sender), *tx.Nonce)
+	}
+
+	s.logger.Info("Queued transaction cancelled", zap.String("hash", tx.Hash))
+	return c.NoContent(http.StatusNoContent)
+}
+
+// pendingTransaction loads the transaction of the :hash path parameter and
+// makes sure it can still be replaced, or cancelled if allowQueued is set.
+func (s *Server) pendingTransaction(c echo.Context, allowQueued bool) (*models.Transaction, error) {
+	hash, err := parseTxHash(c.Param("hash"))
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.GetTransaction(c.Request().Context(), hash.String())
+	if err != nil {
+		if errors.Is(err, database.ErrTxNotFound) {
+			return nil, ErrTxNotFound
+		}
+		s.logger.Error("Failed to get transaction", zap.String("hash", hash.String()), zap.Error(err))
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to get transaction"))
+	}
+
+	if allowQueued && tx.Status == models.StatusBroadcasting {
+		return nil, ErrTxBroadcastMeanwhile
+	}
+	if tx.Status != models.StatusPending && (!allowQueued || tx.Status != models.StatusQueued) {
+		return nil, ErrTxNotPending
+	}
+	return tx, nil
+}
@@ -0,0 +1,232 @@
+package server
+
+import (
+	"app/internal/blockchain"
+	"app/internal/cron"
+	"app/internal/database"
+	"app/internal/database/models"
+	"context"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func (s *Server) createSchedule(c echo.Context) error {
+	var req ScheduleRequest
+	if err := c.Bind(&req); err != nil {
+		s.logger.Warn("Invalid request body", zap.Error(err))
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request format")
+	}
+
+	if req.From == "" {
+		return ErrMissingScheduleSender
+	}
+	signer, err := s.resolveSigner(req.From, "")
+	if err != nil {
+		return err
+	}
+
+	token, err := s.resolveToken(c.Request().Context(), req.Token)
+	if err != nil {
+		return err
+	}
+
+	schedule := &models.Schedule{Sender: strings.ToLower(signer.Address().Hex()), Status: models.ScheduleActive}
+	if err := applyScheduleRequest(schedule, req, token, time.Now()); err != nil {
+		return err
+	}
+
+	if err := s.db.CreateSchedule(c.Request().Context(), schedule); err != nil {
+		s.logger.Error("Failed to create schedule", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to create schedule"))
+	}
+
+	s.logger.Info("Schedule created", zap.Uint64("schedule_id", schedule.ID), zap.String("sender", schedule.Sender))
+	return c.JSON(http.StatusCreated, newScheduleResponse(schedule))
+}
+
+func (s *Server) listSchedules(c echo.Context) error {
+	schedules, err := s.db.GetSchedules(c.Request().Context())
+	if err != nil {
+		s.logger.Error("Failed to list schedules", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to list schedules"))
+	}
+
+	response := make([]*ScheduleResponse, 0, len(schedules))
+	for i := range schedules {
+		response = append(response, newScheduleResponse(&schedules[i]))
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+func (s *Server) getSchedule(c echo.Context) error {
+	schedule, err := s.loadSchedule(c)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, newScheduleResponse(schedule))
+}
+
+// updateSchedule replaces the transfer and timing of a schedule and reactivates it.
+// The sender cannot be changed.
+func (s *Server) updateSchedule(c echo.Context) error {
+	schedule, err := s.loadSchedule(c)
+	if err != nil {
+		return err
+	}
+	if schedule.Status == models.ScheduleCancelled {
+		return ErrScheduleCancelled
+	}
+
+	var req ScheduleRequest
+	if err := c.Bind(&req); err != nil {
+		s.logger.Warn("Invalid request body", zap.Error(err))
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request format")
+	}
+	if req.From != "" && !strings.EqualFold(req.From, schedule.Sender) {
+		return ErrInvalidSenderAddress
+	}
+	token, err := s.resolveToken(c.Request().Context(), req.Token)
+	if err != nil {
+		return err
+	}
+
+	schedule.Cron = nil
+	schedule.Status = models.ScheduleActive
+	if err := applyScheduleRequest(schedule, req, token, time.Now()); err != nil {
+		return err
+	}
+
+	if err := s.db.UpdateSchedule(c.Request().Context(), schedule); err != nil {
+		s.logger.Error("Failed to update schedule", zap.Uint64("schedule_id", schedule.ID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to update schedule"))
+	}
+
+	s.logger.Info("Schedule updated", zap.Uint64("schedule_id", schedule.ID))
+	return c.JSON(http.StatusOK, newScheduleResponse(schedule))
+}
+
+// deleteSchedule cancels a schedule. It is kept, since executed transfers refer to it.
+func (s *Server) deleteSchedule(c echo.Context) error {
+	schedule, err := s.loadSchedule(c)
+	if err != nil {
+		return err
+	}
+
+	schedule.Status = models.ScheduleCancelled
+	schedule.NextRunAt = nil
+	if err := s.db.UpdateSchedule(c.Request().Context(), schedule); err != nil {
+		s.logger.Error("Failed to cancel schedule", zap.Uint64("schedule_id", schedule.ID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to cancel schedule"))
+	}
+
+	s.logger.Info("Schedule cancelled", zap.Uint64("schedule_id", schedule.ID))
+	return c.NoContent(http.StatusNoContent)
+}
+
+// SubmitScheduled sends the transfer of a due schedule through the regular send path.
+func (s *Server) SubmitScheduled(ctx context.Context, schedule *models.Schedule) (string, error) {
+	signer, err := s.resolveSigner(schedule.Sender, "")
+	if err != nil {
+		return "", errorWithMessage(err)
+	}
+
+	fees, err := parseFeeParams(FeeParams{Speed: schedule.Speed})
+	if err != nil {
+		return "", errorWithMessage(err)
+	}
+
+	signedTx, err := s.submitTransfer(ctx, signer, schedule.Receiver, schedule.Amount.BigInt(), storedToken(schedule.Token, schedule.TokenAddress), fees, transferMeta{scheduleID: &schedule.ID})
+	if err != nil {
+		return "", errorWithMessage(err)
+	}
+	return signedTx.Hash().String(), nil
+}
+
+func (s *Server) loadSchedule(c echo.Context) (*models.Schedule, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, ErrScheduleNotFound
+	}
+
+	schedule, err := s.db.GetSchedule(c.Request().Context(), id)
+	if err != nil {
+		if errors.Is(err, database.ErrScheduleNotFound) {
+			return nil, ErrScheduleNotFound
+		}
+		s.logger.Error("Failed to get schedule", zap.Uint64("schedule_id", id), zap.Error(err))
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to get schedule"))
+	}
+	return schedule, nil
+}
+
+// applyScheduleRequest validates req and copies it with the resolved token to schedule.
+func applyScheduleRequest(schedule *models.Schedule, req ScheduleRequest, token transferToken, now time.Time) error {
+	if _, err := blockchain.ParseFeeTier(req.Speed); err != nil {
+		return ErrInvalidFeeTier
+	}
+	if !isValidAddress(req.Receiver) {
+		return ErrInvalidReceiverAddress
+	}
+	amount, err := parseAmount(req.Amount)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case (req.RunAt == nil) == (req.Cron == ""):
+		return ErrInvalidScheduleTime
+	case req.RunAt != nil:
+		if !req.RunAt.After(now) {
+			return ErrScheduleInPast
+		}
+		runAt := req.RunAt.UTC()
+		schedule.NextRunAt = &runAt
+	default:
+		expr, err := cron.Parse(req.Cron)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, errors.Wrap(err, "invalid cron").Error())
+		}
+		next, err := expr.Next(now.UTC())
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, errors.Wrap(err, "invalid cron").Error())
+		}
+		schedule.Cron = &req.Cron
+		schedule.NextRunAt = &next
+	}
+
+	schedule.Receiver = strings.ToLower(req.Receiver)
+	schedule.Amount = decimal.NewFromBigInt(amount, 0)
+	schedule.Token = token.symbol
+	schedule.TokenAddress = token.address()
+	schedule.Speed = req.Speed
+	return nil
+}
+
+func newScheduleResponse(schedule *models.Schedule) *ScheduleResponse {
+	return &ScheduleResponse{
+		ID:         schedule.ID,
+		From:       schedule.Sender,
+		Receiver:   schedule.Receiver,
+		Amount:     schedule.Amount.String(),
+		Token:      schedule.Token,
+		Speed:      schedule.Speed,
+		Cron:       schedule.Cron,
+		NextRunAt:  schedule.NextRunAt,
+		Status:     schedule.Status,
+		LastRunAt:  schedule.LastRunAt,
+		LastTxHash: schedule.LastTxHash,
+		LastError:  schedule.LastError,
+		CreatedAt:  schedule.CreatedAt,
+	}
+}
+
+// errorWithMessage turns API errors into plain errors for callers outside of a request.
+func errorWithMessage(err error) error {
+	return errors.New(errorMessage(err))
+}
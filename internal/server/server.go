@@ -5,6 +5,8 @@ import (
 	"app/internal/database/models"
 	"context"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/labstack/echo/v4"
 	"github.com/pkg/errors"
@@ -14,30 +16,72 @@ import (
 	"strings"
 
 	"app/internal/database"
+	"app/internal/importer"
+	"app/internal/wallet"
 	"go.uber.org/zap"
 )
 
 type Server struct {
 	logger *zap.Logger
 
-	e  *echo.Echo
-	bc blockchain.Client
-	db database.Database
+	e      *echo.Echo
+	bc     blockchain.Client
+	db     database.Database
+	wallet wallet.Wallet
+
+	importer *importer.Importer
 }
 
-func NewServer(bc blockchain.Client, db database.Database, logger *zap.Logger) *Server {
+// NewServer creates the HTTP server. w may be nil if no keystore is configured and
+// imp may be nil to disable chain history imports.
+func NewServer(bc blockchain.Client, db database.Database, w wallet.Wallet, imp *importer.Importer, logger *zap.Logger) *Server {
 	e := echo.New()
 	s := &Server{
-		e:      e,
-		bc:     bc,
-		db:     db,
-		logger: logger,
+		e:        e,
+		bc:       bc,
+		db:       db,
+		wallet:   w,
+		importer: imp,
+		logger:   logger,
 	}
 
-	e.POST("/transaction/send", s.submitFILTransaction)
+	e.POST("/transaction/send", s.submitTransaction)
+	e.POST("/transaction/raw", s.submitRawTransaction)
+	e.POST("/transaction/prepare", s.prepareFILTransaction)
 	e.GET("/transactions/", s.getTransactions)
+	e.POST("/transactions/batch", s.submitBatch)
+	e.GET("/transactions/batch/:id", s.getBatch)
+	e.GET("/fees", s.getFees)
+	e.GET("/transaction/:hash", s.getTransaction)
+	e.POST("/transaction/:hash/speedup", s.speedUpTransaction)
+	e.POST("/transaction/:hash/cancel", s.cancelTransaction)
 
 	e.GET("/balance/:address", s.getBalance)
+
+	e.GET("/accounts", s.listAccounts)
+	e.POST("/accounts", s.createAccount)
+	e.POST("/accounts/import", s.importAccount)
+
+	e.POST("/schedules", s.createSchedule)
+	e.GET("/schedules", s.listSchedules)
+	e.GET("/schedules/:id", s.getSchedule)
+	e.PUT("/schedules/:id", s.updateSchedule)
+	e.DELETE("/schedules/:id", s.deleteSchedule)
+
+	e.POST("/tokens", s.registerToken)
+	e.GET("/tokens", s.listTokens)
+	e.DELETE("/tokens/:symbol", s.unregisterToken)
+
+	e.POST("/watched-addresses", s.createWatchedAddress)
+	e.GET("/watched-addresses", s.listWatchedAddresses)
+	e.GET("/watched-addresses/:address", s.getWatchedAddress)
+	e.PUT("/watched-addresses/:address", s.updateWatchedAddress)
+	e.DELETE("/watched-addresses/:address", s.deleteWatchedAddress)
+
+	e.POST("/pool/deposit", s.depositToPool)
+	e.POST("/pool/withdraw", s.withdrawFromPool)
+
+	e.POST("/admin/import", s.importHistory)
 	return s
 }
 
@@ -59,20 +103,28 @@ func (s *Server) getBalance(c echo.Context) error {
 		return ErrInvalidAddress
 	}
 
-	balances, err := s.bc.GetBalances(ctx, common.HexToAddress(strings.TrimPrefix(address, "0x")))
+	account := common.HexToAddress(strings.TrimPrefix(address, "0x"))
+	balances, err := s.bc.GetBalances(ctx, account)
 	if err != nil {
 		s.logger.Error("Failed to get iFIL balance", zap.String("address", address), zap.Error(err))
 		return echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to get iFIL balance"))
 	}
 
+	tokens, err := s.tokenBalances(ctx, account)
+	if err != nil {
+		s.logger.Error("Failed to get token balances", zap.String("address", address), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
 	s.logger.Info("Balance retrieved", zap.String("address", address))
 	return c.JSON(http.StatusOK, &BalanceResponse{
-		FIL:  balances.GetFIL().String(),
-		IFIL: balances.GetIFIL().String(),
+		FIL:    balances.GetFIL().String(),
+		IFIL:   balances.GetIFIL().String(),
+		Tokens: tokens,
 	})
 }
 
-func (s *Server) submitFILTransaction(c echo.Context) error {
+func (s *Server) submitTransaction(c echo.Context) error {
 	ctx := c.Request().Context()
 
 	var req SubmitTransactionRequest
@@ -81,9 +133,20 @@ func (s *Server) submitFILTransaction(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid request format")
 	}
 
-	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(req.PrivateKeyHex, "0x"))
+	token, err := s.resolveToken(ctx, req.Token)
+	if err != nil {
+		return err
+	}
+
+	fees, err := parseFeeParams(req.FeeParams)
+	if err != nil {
+		s.logger.Warn("Invalid fee parameters", zap.Error(err))
+		return err
+	}
+
+	signer, err := s.resolveSigner(req.From, req.PrivateKeyHex)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, errors.Wrap(err, "invalid private key"))
+		return err
 	}
 
 	if !isValidAddress(req.Receiver) {
@@ -91,43 +154,289 @@ func (s *Server) submitFILTransaction(c echo.Context) error {
 		return ErrInvalidReceiverAddress
 	}
 
-	amount := big.NewInt(0)
-	amount.SetString(req.Amount, 10)
-	if amount.Cmp(big.NewInt(0)) <= 0 {
-		return ErrInvalidTxAmount
+	amount, err := parseAmount(req.Amount)
+	if err != nil {
+		return err
 	}
-	receiver := common.HexToAddress(strings.TrimPrefix(req.Receiver, "0x"))
-	sender := crypto.PubkeyToAddress(privateKey.PublicKey).Hex()
-	txReceipt, err := s.bc.SubmitFILTransaction(ctx, privateKey, receiver, amount)
+
+	meta, err := parseTransferMeta(req.Memo, req.Labels, req.Reference)
+	if err != nil {
+		return err
+	}
+
+	idempotencyKey := c.Request().Header.Get(idempotencyKeyHeader)
+	if idempotencyKey != "" {
+		replay, err := s.reserveIdempotencyKey(ctx, idempotencyKey, signer.Address().Hex(), req)
+		if err != nil {
+			return err
+		}
+		if replay != nil {
+			return c.JSON(http.StatusCreated, replay)
+		}
+	}
+
+	if idempotencyKey != "" {
+		// the key is completed together with the queued transaction
+		meta.idempotencyKey = &idempotencyKey
+	}
+
+	sender := signer.Address().Hex()
+	signedTx, err := s.submitTransfer(ctx, signer, req.Receiver, amount, token, fees, meta)
 	if err != nil {
 		s.logger.Error("Failed to submit transaction", zap.String("sender", sender), zap.Error(err))
-		return c.JSON(http.StatusInternalServerError, errors.Wrap(err, "failed to submit transaction"))
+		if idempotencyKey != "" {
+			s.releaseIdempotencyKey(context.WithoutCancel(ctx), idempotencyKey)
+		}
+		return chainError(err, "failed to submit transaction")
+	}
+
+	txHash := signedTx.Hash().String()
+	s.logger.Info("Transaction queued", zap.String("hash", txHash), zap.String("sender", sender), zap.String("receiver", req.Receiver), zap.String("token", string(token.symbol)))
+	return c.JSON(http.StatusCreated, SubmitTransactionResponse{Hash: txHash})
+}
+
+func (s *Server) submitRawTransaction(c echo.Context) error {
+	var req SubmitRawTransactionRequest
+	if err := c.Bind(&req); err != nil {
+		s.logger.Warn("Invalid request body", zap.Error(err))
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request format")
 	}
 
-	txHash := txReceipt.Hash().String()
-	senderToLower := strings.ToLower(sender)
-	receiverToLower := strings.ToLower(req.Receiver)
+	rawTx, err := hexutil.Decode(req.RawTx)
+	if err != nil {
+		s.logger.Warn("Invalid raw transaction encoding", zap.Error(err))
+		return ErrInvalidRawTx
+	}
 
-	tx := &models.Transaction{
-		Hash:     txHash,
-		Sender:   senderToLower,
-		Receiver: receiverToLower,
-		Amount:   decimal.NewFromBigInt(amount, 0),
-		Status:   models.StatusPending,
+	signedTx := new(types.Transaction)
+	if err := signedTx.UnmarshalBinary(rawTx); err != nil {
+		s.logger.Warn("Failed to decode raw transaction", zap.Error(err))
+		return ErrInvalidRawTx
 	}
 
-	if err := s.db.SaveTransaction(tx); err != nil {
-		s.logger.Error("Failed to save transaction", zap.String("hash", txHash), zap.Error(err))
-		return echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to save transaction"))
+	chainID := s.bc.ChainID()
+	if signedTx.ChainId().Cmp(chainID) != 0 {
+		s.logger.Warn("Raw transaction chain id mismatch", zap.String("chain_id", signedTx.ChainId().String()))
+		return ErrChainIdMismatch
+	}
+
+	senderAddr, err := types.Sender(types.LatestSignerForChainID(chainID), signedTx)
+	if err != nil {
+		s.logger.Warn("Failed to recover raw transaction sender", zap.Error(err))
+		return ErrInvalidTxSignature
+	}
+
+	if signedTx.To() == nil {
+		return ErrMissingTxReceiver
+	}
+
+	sender := senderAddr.Hex()
+	receiver := signedTx.To().Hex()
+	if err := s.queueTransaction(signedTx, sender, receiver, signedTx.Value(), filToken, transferMeta{}); err != nil {
+		return chainError(err, "failed to submit transaction")
 	}
 
-	s.logger.Info("Transaction submitted", zap.String("hash", txHash), zap.String("sender", sender), zap.String("receiver", req.Receiver))
+	txHash := signedTx.Hash().String()
+	s.logger.Info("Raw transaction queued", zap.String("hash", txHash), zap.String("sender", sender), zap.String("receiver", receiver))
 	return c.JSON(http.StatusCreated, SubmitTransactionResponse{Hash: txHash})
 }
 
+func (s *Server) prepareFILTransaction(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req PrepareTransactionRequest
+	if err := c.Bind(&req); err != nil {
+		s.logger.Warn("Invalid request body", zap.Error(err))
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request format")
+	}
+
+	fees, err := parseFeeParams(req.FeeParams)
+	if err != nil {
+		s.logger.Warn("Invalid fee parameters", zap.Error(err))
+		return err
+	}
+
+	if !isValidAddress(req.Sender) {
+		s.logger.Warn("Invalid sender address", zap.String("sender", req.Sender))
+		return ErrInvalidSenderAddress
+	}
+
+	if !isValidAddress(req.Receiver) {
+		s.logger.Warn("Invalid receiver address", zap.String("receiver", req.Receiver))
+		return ErrInvalidReceiverAddress
+	}
+
+	amount, err := parseAmount(req.Amount)
+	if err != nil {
+		return err
+	}
+
+	sender := common.HexToAddress(strings.TrimPrefix(req.Sender, "0x"))
+	receiver := common.HexToAddress(strings.TrimPrefix(req.Receiver, "0x"))
+	tx, err := s.bc.PrepareFILTransaction(ctx, sender, receiver, amount, fees)
+	if err != nil {
+		s.logger.Error("Failed to prepare transaction", zap.String("sender", req.Sender), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to prepare transaction"))
+	}
+
+	unsignedTx, err := tx.MarshalBinary()
+	if err != nil {
+		s.logger.Error("Failed to encode prepared transaction", zap.String("sender", req.Sender), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to encode transaction"))
+	}
+
+	s.logger.Info("Transaction prepared", zap.String("sender", req.Sender), zap.String("receiver", req.Receiver), zap.Uint64("nonce", tx.Nonce()))
+	return c.JSON(http.StatusOK, &PrepareTransactionResponse{
+		Type:                 tx.Type(),
+		ChainId:              tx.ChainId().String(),
+		Nonce:                tx.Nonce(),
+		To:                   tx.To().Hex(),
+		Value:                tx.Value().String(),
+		Gas:                  tx.Gas(),
+		MaxFeePerGas:         tx.GasFeeCap().String(),
+		MaxPriorityFeePerGas: tx.GasTipCap().String(),
+		SigningHash:          types.LatestSignerForChainID(tx.ChainId()).Hash(tx).Hex(),
+		UnsignedTx:           hexutil.Encode(unsignedTx),
+	})
+}
+
+// resolveSigner returns the signer of a managed account if from is set, or
+// a local signer for the raw private key otherwise.
+func (s *Server) resolveSigner(from, privateKeyHex string) (blockchain.Signer, error) {
+	if from != "" {
+		if !isValidAddress(from) {
+			s.logger.Warn("Invalid sender address", zap.String("from", from))
+			return nil, ErrInvalidSenderAddress
+		}
+		if s.wallet == nil {
+			return nil, ErrWalletDisabled
+		}
+
+		signer, err := s.wallet.Signer(common.HexToAddress(strings.TrimPrefix(from, "0x")))
+		if err != nil {
+			if errors.Is(err, wallet.ErrAccountNotFound) {
+				return nil, ErrUnknownAccount
+			}
+			s.logger.Error("Failed to get account signer", zap.String("from", from), zap.Error(err))
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to get account signer"))
+		}
+		return signer, nil
+	}
+
+	if privateKeyHex == "" {
+		return nil, ErrMissingSigner
+	}
+
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(privateKeyHex, "0x"))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, errors.Wrap(err, "invalid private key"))
+	}
+	return blockchain.NewLocalSigner(privateKey), nil
+}
+
+// transferMeta links a transfer to the batch, schedule or idempotency key it was
+// sent for and holds the business context given by the caller.
+type transferMeta struct {
+	batchID        *uint64
+	scheduleID     *uint64
+	idempotencyKey *string
+	memo           *string
+	labels         []string
+	reference      *string
+}
+
+// submitTransfer signs a transfer and queues it for the broadcaster.
+func (s *Server) submitTransfer(ctx context.Context, signer blockchain.Signer, receiver string, amount *big.Int, token transferToken, fees blockchain.FeeOptions, meta transferMeta) (*types.Transaction, error) {
+	to := common.HexToAddress(strings.TrimPrefix(receiver, "0x"))
+
+	var signedTx *types.Transaction
+	var err error
+	switch {
+	case token.contract != nil:
+		signedTx, err = s.bc.SignTokenTransaction(ctx, signer, *token.contract, to, amount, fees)
+	case token.symbol == models.TokenIFIL:
+		signedTx, err = s.bc.SignIFILTransaction(ctx, signer, to, amount, fees)
+	default:
+		signedTx, err = s.bc.SignFILTransaction(ctx, signer, to, amount, fees)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// the transaction is recorded before it can reach the chain, the broadcaster sends it
+	if err := s.queueTransaction(signedTx, signer.Address().Hex(), receiver, amount, token, meta); err != nil {
+		s.bc.ReleaseNonce(signer.Address(), signedTx.Nonce())
+		return nil, err
+	}
+	return signedTx, nil
+}
+
+// queueTransaction stores a signed transaction for the broadcaster.
+func (s *Server) queueTransaction(tx *types.Transaction, sender, receiver string, amount *big.Int, token transferToken, meta transferMeta) error {
+	row := &models.Transaction{
+		Sender:         strings.ToLower(sender),
+		Receiver:       strings.ToLower(receiver),
+		Amount:         decimal.NewFromBigInt(amount, 0),
+		Token:          token.symbol,
+		TokenAddress:   token.address(),
+		Kind:           models.KindTransfer,
+		BatchID:        meta.batchID,
+		ScheduleID:     meta.scheduleID,
+		IdempotencyKey: meta.idempotencyKey,
+		Memo:           meta.memo,
+		Labels:         meta.labels,
+		Reference:      meta.reference,
+	}
+	if err := setTxDetails(row, tx); err != nil {
+		return err
+	}
+	return s.saveTransaction(row)
+}
+
+// setTxDetails queues row for the signed tx and copies its submission parameters.
+func setTxDetails(row *models.Transaction, tx *types.Transaction) error {
+	rawTx, err := tx.MarshalBinary()
+	if err != nil {
+		return errors.Wrap(err, "failed to encode transaction")
+	}
+	encoded := hexutil.Encode(rawTx)
+	row.Hash = tx.Hash().String()
+	row.Status = models.StatusQueued
+	row.RawTx = &encoded
+
+	nonce, gasLimit, txType := tx.Nonce(), tx.Gas(), tx.Type()
+	gasFeeCap := decimal.NewFromBigInt(tx.GasFeeCap(), 0)
+	gasTipCap := decimal.NewFromBigInt(tx.GasTipCap(), 0)
+
+	row.Nonce = &nonce
+	row.GasLimit = &gasLimit
+	row.GasFeeCap = &gasFeeCap
+	row.GasTipCap = &gasTipCap
+	row.TxType = &txType
+	if chainID := tx.ChainId(); chainID != nil && chainID.Sign() > 0 {
+		id := chainID.Uint64()
+		row.ChainID = &id
+	}
+	return nil
+}
+
+func (s *Server) saveTransaction(tx *models.Transaction) error {
+	if err := s.db.SaveTransaction(tx); err != nil {
+		if errors.Is(err, database.ErrTxExists) {
+			return ErrTxExists
+		}
+		s.logger.Error("Failed to save transaction", zap.String("hash", tx.Hash), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to save transaction"))
+	}
+	return nil
+}
+
 func (s *Server) getTransactions(c echo.Context) error {
 	sender := c.QueryParam("sender")
 	receiver := c.QueryParam("receiver")
+	label := c.QueryParam("label")
+	reference := c.QueryParam("reference")
+	direction := models.TransactionDirection(c.QueryParam("direction"))
 
 	if sender != "" && !isValidAddress(sender) {
 		s.logger.Warn("Invalid sender address", zap.String("sender", sender))
@@ -139,7 +448,18 @@ func (s *Server) getTransactions(c echo.Context) error {
 		return ErrInvalidReceiverAddress
 	}
 
-	txs, err := s.db.GetTransactions(c.Request().Context(), strings.ToLower(sender), strings.ToLower(receiver), 0)
+	if direction != "" && direction != models.DirectionIncoming && direction != models.DirectionOutgoing {
+		s.logger.Warn("Invalid direction", zap.String("direction", string(direction)))
+		return ErrInvalidDirection
+	}
+
+	txs, err := s.db.GetTransactions(c.Request().Context(), database.TransactionFilter{
+		Sender:    strings.ToLower(sender),
+		Receiver:  strings.ToLower(receiver),
+		Label:     label,
+		Reference: reference,
+		Direction: direction,
+	})
 	if err != nil {
 		s.logger.Error("failed to retrieve transactions", zap.Error(err), zap.String("sender", sender), zap.String("receiver", receiver))
 		return echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to retrieve transactions"))
@@ -149,6 +469,36 @@ func (s *Server) getTransactions(c echo.Context) error {
 	return c.JSON(http.StatusOK, txs)
 }
 
+// chainError maps errors of the blockchain client and signers to API errors.
+func chainError(err error, message string) error {
+	var httpErr *echo.HTTPError
+	switch {
+	case errors.As(err, &httpErr):
+		return httpErr
+	case errors.Is(err, wallet.ErrSignerRejected):
+		return ErrSignerRejected
+	case errors.Is(err, wallet.ErrSignerTimeout):
+		return ErrSignerTimeout
+	case errors.Is(err, blockchain.ErrInvalidFeeCaps):
+		return ErrInvalidFeeCaps
+	case errors.Is(err, blockchain.ErrFeeBumpTooLow):
+		return ErrFeeBumpTooLow
+	case errors.Is(err, blockchain.ErrTxNotPending):
+		return ErrTxNotPending
+	case errors.Is(err, blockchain.ErrSignerMismatch):
+		return ErrSignerMismatch
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, message))
+}
+
+func parseAmount(s string) (*big.Int, error) {
+	amount, ok := new(big.Int).SetString(s, 10)
+	if !ok || amount.Sign() <= 0 {
+		return nil, ErrInvalidTxAmount
+	}
+	return amount, nil
+}
+
 func isValidAddress(addr string) bool {
 	return common.IsHexAddress(strings.TrimSpace(addr))
 }
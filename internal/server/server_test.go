@@ -3,11 +3,20 @@ package server
 import (
 	"app/internal/blockchain"
 	blockchainmock "app/internal/blockchain/mock"
+	"app/internal/database"
 	dbmock "app/internal/database/mock"
+	"app/internal/database/models"
+	"app/internal/importer"
+	walletmock "app/internal/wallet/mock"
 	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
@@ -17,6 +26,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestGetBalance_Success(t *testing.T) {
@@ -32,7 +42,7 @@ func TestGetBalance_Success(t *testing.T) {
 	mockClient := blockchainmock.NewMockClient(ctrl)
 	mockDatabase := dbmock.NewMockDatabase(ctrl)
 
-	srv := NewServer(mockClient, mockDatabase, logger)
+	srv := NewServer(mockClient, mockDatabase, nil, nil, logger)
 	go srv.Start(":8080")
 	defer srv.Stop(context.Background())
 
@@ -41,6 +51,21 @@ func TestGetBalance_Success(t *testing.T) {
 		GetBalances(gomock.Any(), common.HexToAddress(strings.TrimPrefix(address, "0x"))).
 		Return(expectedBalances, nil)
 
+	const (
+		usdfc  = "0xb3042734b608a1b16e9e86b374a3f3e389b4cdf0"
+		broken = "0x5d5d4d04b70bfe49ad7aac8c4454536070daf180"
+	)
+	mockDatabase.EXPECT().
+		GetRegisteredTokens(gomock.Any()).
+		Return([]models.RegisteredToken{{Address: usdfc, Symbol: "USDFC", Decimals: 6}, {Address: broken, Symbol: "BRKN", Decimals: 18}}, nil)
+	mockClient.EXPECT().
+		TokenBalance(gomock.Any(), common.HexToAddress(usdfc), common.HexToAddress(address)).
+		Return(big.NewInt(12_500_000), nil)
+	// a failing token contract does not fail the other balances
+	mockClient.EXPECT().
+		TokenBalance(gomock.Any(), common.HexToAddress(broken), common.HexToAddress(address)).
+		Return(nil, errors.New("execution reverted"))
+
 	testServer := httptest.NewServer(srv.e)
 	defer testServer.Close()
 
@@ -59,4 +84,1263 @@ func TestGetBalance_Success(t *testing.T) {
 
 	require.Equal(t, fmt.Sprintf("%v", expectedFILBalance), response.FIL)
 	require.Equal(t, fmt.Sprintf("%v", expectedIFILBalance), response.IFIL)
+	require.Equal(t, []TokenBalanceResponse{
+		{Symbol: "USDFC", Address: usdfc, Balance: "12.5"},
+		{Symbol: "BRKN", Address: broken, Error: "failed to get balance"},
+	}, response.Tokens)
+}
+
+func TestSubmitRawTransaction_Success(t *testing.T) {
+	const receiver = "0xa512eb36e162bfb0e9f55b56bc2a070ca0d3ecd7"
+	chainID := big.NewInt(314159)
+
+	logger := zap.NewNop()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	srv := NewServer(mockClient, mockDatabase, nil, nil, logger)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+
+	to := common.HexToAddress(receiver)
+	signedTx, err := types.SignNewTx(key, types.LatestSignerForChainID(chainID), &types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     1,
+		GasTipCap: big.NewInt(1e9),
+		GasFeeCap: big.NewInt(3e9),
+		Gas:       21000,
+		To:        &to,
+		Value:     big.NewInt(1000),
+	})
+	require.NoError(t, err)
+	rawTx, err := signedTx.MarshalBinary()
+	require.NoError(t, err)
+
+	mockClient.EXPECT().ChainID().Return(chainID)
+	mockDatabase.EXPECT().
+		SaveTransaction(gomock.Any()).
+		DoAndReturn(func(tx *models.Transaction) error {
+			require.Equal(t, signedTx.Hash().Hex(), tx.Hash)
+			require.Equal(t, strings.ToLower(sender.Hex()), tx.Sender)
+			require.Equal(t, receiver, tx.Receiver)
+			require.Equal(t, "1000", tx.Amount.String())
+			require.Equal(t, models.StatusQueued, tx.Status)
+			require.Equal(t, hexutil.Encode(rawTx), *tx.RawTx)
+			require.Equal(t, models.TokenFIL, tx.Token)
+			return nil
+		})
+
+	testServer := httptest.NewServer(srv.e)
+	defer testServer.Close()
+
+	reqBody := fmt.Sprintf(`{"raw_tx":"%s"}`, hexutil.Encode(rawTx))
+	resp, err := http.Post(testServer.URL+"/transaction/raw", "application/json", strings.NewReader(reqBody))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	response := &SubmitTransactionResponse{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(response))
+	require.Equal(t, signedTx.Hash().Hex(), response.Hash)
+}
+
+func TestSubmitTransaction_IFIL(t *testing.T) {
+	const (
+		receiver = "0xa512eb36e162bfb0e9f55b56bc2a070ca0d3ecd7"
+		amount   = "5000"
+	)
+
+	logger := zap.NewNop()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	srv := NewServer(mockClient, mockDatabase, nil, nil, logger)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+
+	submittedTx := types.NewTx(&types.DynamicFeeTx{Nonce: 1})
+	mockClient.EXPECT().
+		SignIFILTransaction(gomock.Any(), gomock.Any(), common.HexToAddress(receiver), big.NewInt(5000), gomock.Any()).
+		DoAndReturn(func(_ context.Context, signer blockchain.Signer, _ common.Address, _ *big.Int, fees blockchain.FeeOptions) (*types.Transaction, error) {
+			require.Equal(t, sender, signer.Address())
+			require.Equal(t, blockchain.FeeTierFast, fees.Tier)
+			require.Equal(t, big.NewInt(4e9), fees.GasFeeCap)
+			require.Nil(t, fees.GasTipCap)
+			return submittedTx, nil
+		})
+	mockDatabase.EXPECT().
+		SaveTransaction(gomock.Any()).
+		DoAndReturn(func(tx *models.Transaction) error {
+			require.Equal(t, submittedTx.Hash().Hex(), tx.Hash)
+			require.Equal(t, strings.ToLower(sender.Hex()), tx.Sender)
+			require.Equal(t, receiver, tx.Receiver)
+			require.Equal(t, amount, tx.Amount.String())
+			require.Equal(t, models.TokenIFIL, tx.Token)
+			require.Equal(t, uint64(1), *tx.Nonce)
+			require.Equal(t, uint8(types.DynamicFeeTxType), *tx.TxType)
+			return nil
+		})
+
+	testServer := httptest.NewServer(srv.e)
+	defer testServer.Close()
+
+	reqBody := fmt.Sprintf(`{"private_key_hex":"%x","receiver":"%s","amount":"%s","token":"iFIL","speed":"fast","max_fee_per_gas":"4000000000"}`, crypto.FromECDSA(key), receiver, amount)
+	resp, err := http.Post(testServer.URL+"/transaction/send", "application/json", strings.NewReader(reqBody))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	response := &SubmitTransactionResponse{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(response))
+	require.Equal(t, submittedTx.Hash().Hex(), response.Hash)
+}
+
+func TestSpeedUpTransaction(t *testing.T) {
+	const (
+		rootHash     = "0x4033cf2e690e8f6078ab9e665be681c1a9d7c79b6a75d0668da5336fabf43b46"
+		originalHash = "0x15e54e2f6e60be523a4ef44e3dc3ab7245bdc98d8b007bfcf1628a320983384b"
+		receiver     = "0xa512eb36e162bfb0e9f55b56bc2a070ca0d3ecd7"
+	)
+
+	logger := zap.NewNop()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	srv := NewServer(mockClient, mockDatabase, nil, nil, logger)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sender := strings.ToLower(crypto.PubkeyToAddress(key.PublicKey).Hex())
+
+	root := rootHash
+	scheduleID := uint64(7)
+	mockDatabase.EXPECT().
+		GetTransaction(gomock.Any(), originalHash).
+		Return(&models.Transaction{
+			Hash:       originalHash,
+			Sender:     sender,
+			Receiver:   receiver,
+			Status:     models.StatusPending,
+			Token:      models.TokenFIL,
+			Replaces:   &root,
+			ScheduleID: &scheduleID,
+		}, nil)
+
+	replacementTx := types.NewTx(&types.DynamicFeeTx{Nonce: 1, GasFeeCap: big.NewInt(2)})
+	mockClient.EXPECT().
+		SpeedUpTransaction(gomock.Any(), gomock.Any(), common.HexToHash(originalHash), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ blockchain.Signer, _ common.Hash, fees blockchain.FeeOptions) (*types.Transaction, error) {
+			require.Equal(t, blockchain.FeeTierFast, fees.Tier)
+			return replacementTx, nil
+		})
+	mockDatabase.EXPECT().
+		SaveTransaction(gomock.Any()).
+		DoAndReturn(func(tx *models.Transaction) error {
+			require.Equal(t, replacementTx.Hash().Hex(), tx.Hash)
+			require.Equal(t, sender, tx.Sender)
+			require.Equal(t, rootHash, *tx.Replaces)
+			// the run of the schedule keeps its transfer
+			require.Equal(t, scheduleID, *tx.ScheduleID)
+			return nil
+		})
+
+	testServer := httptest.NewServer(srv.e)
+	defer testServer.Close()
+
+	reqBody := fmt.Sprintf(`{"private_key_hex":"%x","speed":"fast"}`, crypto.FromECDSA(key))
+	resp, err := http.Post(testServer.URL+"/transaction/"+originalHash+"/speedup", "application/json", strings.NewReader(reqBody))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	response := &ReplaceTransactionResponse{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(response))
+	require.Equal(t, replacementTx.Hash().Hex(), response.Hash)
+	require.Equal(t, rootHash, response.Replaces)
+}
+
+func TestCancelTransaction(t *testing.T) {
+	const (
+		originalHash = "0x15e54e2f6e60be523a4ef44e3dc3ab7245bdc98d8b007bfcf1628a320983384b"
+		receiver     = "0xa512eb36e162bfb0e9f55b56bc2a070ca0d3ecd7"
+	)
+
+	logger := zap.NewNop()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	srv := NewServer(mockClient, mockDatabase, nil, nil, logger)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sender := strings.ToLower(crypto.PubkeyToAddress(key.PublicKey).Hex())
+
+	mockDatabase.EXPECT().
+		GetTransaction(gomock.Any(), originalHash).
+		Return(&models.Transaction{
+			Hash:     originalHash,
+			Sender:   sender,
+			Receiver: receiver,
+			Status:   models.StatusPending,
+			Token:    models.TokenIFIL,
+		}, nil)
+
+	cancelTx := types.NewTx(&types.DynamicFeeTx{Nonce: 1})
+	mockClient.EXPECT().
+		CancelTransaction(gomock.Any(), gomock.Any(), common.HexToHash(originalHash), gomock.Any()).
+		Return(cancelTx, nil)
+	mockDatabase.EXPECT().
+		SaveTransaction(gomock.Any()).
+		DoAndReturn(func(tx *models.Transaction) error {
+			require.Equal(t, cancelTx.Hash().Hex(), tx.Hash)
+			require.Equal(t, sender, tx.Receiver)
+			require.True(t, tx.Amount.IsZero())
+			require.Equal(t, models.TokenFIL, tx.Token)
+			require.Equal(t, models.KindCancel, tx.Kind)
+			require.Equal(t, originalHash, *tx.Replaces)
+			return nil
+		})
+
+	testServer := httptest.NewServer(srv.e)
+	defer testServer.Close()
+
+	reqBody := fmt.Sprintf(`{"private_key_hex":"%x"}`, crypto.FromECDSA(key))
+	resp, err := http.Post(testServer.URL+"/transaction/"+originalHash+"/cancel", "application/json", strings.NewReader(reqBody))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	response := &ReplaceTransactionResponse{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(response))
+	require.Equal(t, cancelTx.Hash().Hex(), response.Hash)
+}
+
+func TestCancelTransaction_Queued(t *testing.T) {
+	const queuedHash = "0x15e54e2f6e60be523a4ef44e3dc3ab7245bdc98d8b007bfcf1628a320983384b"
+
+	logger := zap.NewNop()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	srv := NewServer(mockClient, mockDatabase, nil, nil, logger)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	nonce := uint64(4)
+
+	// never broadcast, so it is cancelled instead of replaced
+	mockDatabase.EXPECT().
+		GetTransaction(gomock.Any(), queuedHash).
+		Return(&models.Transaction{
+			Hash:   queuedHash,
+			Sender: strings.ToLower(address.Hex()),
+			Status: models.StatusQueued,
+			Nonce:  &nonce,
+		}, nil).
+		Times(2)
+	mockDatabase.EXPECT().CancelQueuedTransaction(gomock.Any(), queuedHash).Return(nil)
+	mockClient.EXPECT().ReleaseNonce(address, nonce)
+
+	testServer := httptest.NewServer(srv.e)
+	defer testServer.Close()
+
+	reqBody := fmt.Sprintf(`{"private_key_hex":"%x"}`, crypto.FromECDSA(key))
+	resp, err := http.Post(testServer.URL+"/transaction/"+queuedHash+"/cancel", "application/json", strings.NewReader(reqBody))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	// claimed by the broadcaster between loading and cancelling it
+	mockDatabase.EXPECT().CancelQueuedTransaction(gomock.Any(), queuedHash).Return(database.ErrTxNotFound)
+
+	resp, err = http.Post(testServer.URL+"/transaction/"+queuedHash+"/cancel", "application/json", strings.NewReader(reqBody))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	// being sent, so it can be neither cancelled nor replaced yet
+	mockDatabase.EXPECT().
+		GetTransaction(gomock.Any(), queuedHash).
+		Return(&models.Transaction{Hash: queuedHash, Sender: strings.ToLower(address.Hex()), Status: models.StatusBroadcasting}, nil)
+
+	resp, err = http.Post(testServer.URL+"/transaction/"+queuedHash+"/cancel", "application/json", strings.NewReader(reqBody))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	// speeding up a queued transaction is still rejected
+	mockDatabase.EXPECT().
+		GetTransaction(gomock.Any(), queuedHash).
+		Return(&models.Transaction{Hash: queuedHash, Sender: strings.ToLower(address.Hex()), Status: models.StatusQueued}, nil)
+
+	resp, err = http.Post(testServer.URL+"/transaction/"+queuedHash+"/speedup", "application/json", strings.NewReader(reqBody))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusConflict, resp.StatusCode)
+}
+
+func TestPrepareFILTransaction(t *testing.T) {
+	const (
+		sender   = "0x5d5d4d04b70bfe49ad7aac8c4454536070daf180"
+		receiver = "0xa512eb36e162bfb0e9f55b56bc2a070ca0d3ecd7"
+	)
+
+	logger := zap.NewNop()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	srv := NewServer(mockClient, mockDatabase, nil, nil, logger)
+
+	to := common.HexToAddress(receiver)
+	preparedTx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   big.NewInt(314159),
+		Nonce:     3,
+		GasTipCap: big.NewInt(1e9),
+		GasFeeCap: big.NewInt(3e9),
+		Gas:       21000,
+		To:        &to,
+		Value:     big.NewInt(5000),
+	})
+	mockClient.EXPECT().
+		PrepareFILTransaction(gomock.Any(), common.HexToAddress(sender), to, big.NewInt(5000), gomock.Any()).
+		Return(preparedTx, nil)
+
+	testServer := httptest.NewServer(srv.e)
+	defer testServer.Close()
+
+	prepare := func(amount string) *http.Response {
+		reqBody := fmt.Sprintf(`{"sender":"%s","receiver":"%s","amount":"%s"}`, sender, receiver, amount)
+		resp, err := http.Post(testServer.URL+"/transaction/prepare", "application/json", strings.NewReader(reqBody))
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := prepare("5000")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	response := &PrepareTransactionResponse{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(response))
+	require.Equal(t, "314159", response.ChainId)
+	require.Equal(t, uint64(3), response.Nonce)
+	require.Equal(t, to.Hex(), response.To)
+	require.Equal(t, "5000", response.Value)
+	require.Equal(t, uint64(21000), response.Gas)
+	require.Equal(t, types.LatestSignerForChainID(preparedTx.ChainId()).Hash(preparedTx).Hex(), response.SigningHash)
+
+	// malformed amounts are rejected before the chain is asked
+	for _, amount := range []string{"12abc", "-5", "0", ""} {
+		resp := prepare(amount)
+		resp.Body.Close()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode, amount)
+	}
+}
+
+func TestGetFees(t *testing.T) {
+	const (
+		sender   = "0x5d5d4d04b70bfe49ad7aac8c4454536070daf180"
+		receiver = "0xa512eb36e162bfb0e9f55b56bc2a070ca0d3ecd7"
+	)
+
+	logger := zap.NewNop()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	srv := NewServer(mockClient, mockDatabase, nil, nil, logger)
+
+	tier := &blockchain.FeeSuggestion{GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2), EstimatedCost: big.NewInt(3), MaxCost: big.NewInt(4)}
+	mockClient.EXPECT().
+		SuggestFees(gomock.Any(), common.HexToAddress(sender), common.HexToAddress(receiver), big.NewInt(5000)).
+		Return(&blockchain.FeeSuggestions{
+			BaseFee:  big.NewInt(100),
+			GasLimit: 21000,
+			Tiers:    map[blockchain.FeeTier]*blockchain.FeeSuggestion{blockchain.FeeTierSlow: tier, blockchain.FeeTierStandard: tier, blockchain.FeeTierFast: tier},
+		}, nil)
+
+	testServer := httptest.NewServer(srv.e)
+	defer testServer.Close()
+
+	getFees := func(amount string) *http.Response {
+		resp, err := http.Get(fmt.Sprintf("%s/fees?sender=%s&receiver=%s&amount=%s", testServer.URL, sender, receiver, amount))
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := getFees("5000")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	response := &FeesResponse{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(response))
+	require.Equal(t, "100", response.BaseFee)
+	require.Equal(t, "2", response.Standard.MaxFeePerGas)
+
+	// malformed amounts are rejected before the chain is asked
+	for _, amount := range []string{"12abc", "-5", "0", ""} {
+		resp := getFees(amount)
+		resp.Body.Close()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode, amount)
+	}
+}
+
+func TestGetTransaction_ChainOnly(t *testing.T) {
+	const receiver = "0xa512eb36e162bfb0e9f55b56bc2a070ca0d3ecd7"
+
+	logger := zap.NewNop()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	srv := NewServer(mockClient, mockDatabase, nil, nil, logger)
+
+	to := common.HexToAddress(receiver)
+	chainTx := types.NewTx(&types.DynamicFeeTx{Nonce: 7, To: &to, Value: big.NewInt(1000)})
+	hash := chainTx.Hash()
+
+	mockDatabase.EXPECT().
+		GetTransaction(gomock.Any(), hash.Hex()).
+		Return(nil, database.ErrTxNotFound)
+	mockClient.EXPECT().
+		GetTransaction(gomock.Any(), hash).
+		Return(&blockchain.TransactionInfo{
+			Tx: chainTx,
+			Receipt: &types.Receipt{
+				Status:            types.ReceiptStatusSuccessful,
+				BlockNumber:       big.NewInt(100),
+				GasUsed:           1000,
+				EffectiveGasPrice: big.NewInt(3),
+			},
+			Confirmations: 5,
+		}, nil)
+
+	testServer := httptest.NewServer(srv.e)
+	defer testServer.Close()
+
+	resp, err := http.Get(testServer.URL + "/transaction/" + hash.Hex())
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	response := &TransactionResponse{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(response))
+	require.Nil(t, response.Stored)
+	require.NotNil(t, response.Chain)
+	require.Equal(t, to.Hex(), response.Chain.To)
+	require.Equal(t, uint64(7), response.Chain.Nonce)
+	require.Equal(t, "confirmed", response.Chain.Status)
+	require.Equal(t, uint64(100), *response.Chain.BlockNumber)
+	require.Equal(t, uint64(5), response.Chain.Confirmations)
+	require.Equal(t, "3000", response.Chain.FeePaid)
+}
+
+func TestGetTransaction_NotFound(t *testing.T) {
+	const hash = "0x15e54e2f6e60be523a4ef44e3dc3ab7245bdc98d8b007bfcf1628a320983384b"
+
+	logger := zap.NewNop()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	srv := NewServer(mockClient, mockDatabase, nil, nil, logger)
+
+	mockDatabase.EXPECT().GetTransaction(gomock.Any(), hash).Return(nil, database.ErrTxNotFound)
+	mockClient.EXPECT().GetTransaction(gomock.Any(), common.HexToHash(hash)).Return(nil, blockchain.ErrTxNotFound)
+
+	testServer := httptest.NewServer(srv.e)
+	defer testServer.Close()
+
+	resp, err := http.Get(testServer.URL + "/transaction/" + hash)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestSubmitTransaction_IdempotencyKey(t *testing.T) {
+	const (
+		receiver = "0xa512eb36e162bfb0e9f55b56bc2a070ca0d3ecd7"
+		key      = "3f1c9a52-transfer"
+	)
+
+	logger := zap.NewNop()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	srv := NewServer(mockClient, mockDatabase, nil, nil, logger)
+
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	// the first request reserves the key, later ones find it completed
+	record := &models.IdempotencyKey{Key: key}
+	submittedTx := types.NewTx(&types.DynamicFeeTx{Nonce: 1})
+	// the fingerprint holds the sender instead of the private key
+	sender := crypto.PubkeyToAddress(privateKey.PublicKey).Hex()
+	fingerprint, err := requestFingerprint(sender, SubmitTransactionRequest{Receiver: receiver, Amount: "5000"})
+	require.NoError(t, err)
+	gomock.InOrder(
+		mockDatabase.EXPECT().
+			ReserveIdempotencyKey(gomock.Any(), key, fingerprint, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _, fingerprint string, staleBefore time.Time) (*models.IdempotencyKey, bool, error) {
+				require.WithinDuration(t, time.Now().Add(-idempotencyKeyTimeout), staleBefore, time.Minute)
+				record.Fingerprint = fingerprint
+				return record, true, nil
+			}),
+		mockDatabase.EXPECT().
+			ReserveIdempotencyKey(gomock.Any(), key, gomock.Any(), gomock.Any()).
+			Return(record, false, nil).
+			Times(2),
+	)
+	mockClient.EXPECT().
+		SignFILTransaction(gomock.Any(), gomock.Any(), common.HexToAddress(receiver), big.NewInt(5000), gomock.Any()).
+		Return(submittedTx, nil)
+	// the key is completed together with the queued transaction
+	mockDatabase.EXPECT().
+		SaveTransaction(gomock.Any()).
+		DoAndReturn(func(tx *models.Transaction) error {
+			require.Equal(t, key, *tx.IdempotencyKey)
+			record.TxHash = &tx.Hash
+			return nil
+		})
+
+	testServer := httptest.NewServer(srv.e)
+	defer testServer.Close()
+
+	send := func(amount string) *http.Response {
+		reqBody := fmt.Sprintf(`{"private_key_hex":"%x","receiver":"%s","amount":"%s"}`, crypto.FromECDSA(privateKey), receiver, amount)
+		req, err := http.NewRequest(http.MethodPost, testServer.URL+"/transaction/send", strings.NewReader(reqBody))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Idempotency-Key", key)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	for i := 0; i < 2; i++ {
+		resp := send("5000")
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		response := &SubmitTransactionResponse{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(response))
+		require.Equal(t, submittedTx.Hash().Hex(), response.Hash)
+		resp.Body.Close()
+	}
+
+	resp := send("6000")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusConflict, resp.StatusCode)
+}
+
+func TestSubmitTransaction_SaveFailureReleasesNonce(t *testing.T) {
+	const receiver = "0xa512eb36e162bfb0e9f55b56bc2a070ca0d3ecd7"
+
+	logger := zap.NewNop()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	srv := NewServer(mockClient, mockDatabase, nil, nil, logger)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+
+	signedTx := types.NewTx(&types.DynamicFeeTx{Nonce: 7})
+	mockClient.EXPECT().
+		SignFILTransaction(gomock.Any(), gomock.Any(), common.HexToAddress(receiver), big.NewInt(5000), gomock.Any()).
+		Return(signedTx, nil)
+	mockDatabase.EXPECT().SaveTransaction(gomock.Any()).Return(fmt.Errorf("connection reset"))
+	mockClient.EXPECT().ReleaseNonce(sender, uint64(7))
+
+	testServer := httptest.NewServer(srv.e)
+	defer testServer.Close()
+
+	reqBody := fmt.Sprintf(`{"private_key_hex":"%x","receiver":"%s","amount":"5000"}`, crypto.FromECDSA(key), receiver)
+	resp, err := http.Post(testServer.URL+"/transaction/send", "application/json", strings.NewReader(reqBody))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+}
+
+func TestSubmitBatch(t *testing.T) {
+	const (
+		alice = "0xa512eb36e162bfb0e9f55b56bc2a070ca0d3ecd7"
+		bob   = "0x0c1b7a8a4f8e6d5c4b3a29181716151413121110"
+	)
+
+	logger := zap.NewNop()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	srv := NewServer(mockClient, mockDatabase, nil, nil, logger)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	testServer := httptest.NewServer(srv.e)
+	defer testServer.Close()
+
+	post := func(transfers string) *http.Response {
+		reqBody := fmt.Sprintf(`{"private_key_hex":"%x","transfers":%s}`, crypto.FromECDSA(key), transfers)
+		resp, err := http.Post(testServer.URL+"/transactions/batch", "application/json", strings.NewReader(reqBody))
+		require.NoError(t, err)
+		return resp
+	}
+
+	// an invalid transfer rejects the whole batch before anything is signed
+	resp := post(fmt.Sprintf(`[{"receiver":"%s","amount":"10"},{"receiver":"%s","amount":"-1"}]`, alice, bob))
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp.Body.Close()
+
+	mockDatabase.EXPECT().
+		CreateBatch(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, batch *models.Batch) error {
+			batch.ID = 3
+			return nil
+		})
+	aliceTx := types.NewTx(&types.DynamicFeeTx{Nonce: 1})
+	bobTx := types.NewTx(&types.DynamicFeeTx{Nonce: 2})
+	gomock.InOrder(
+		mockClient.EXPECT().SignFILTransaction(gomock.Any(), gomock.Any(), common.HexToAddress(alice), big.NewInt(10), gomock.Any()).Return(aliceTx, nil),
+		mockClient.EXPECT().SignFILTransaction(gomock.Any(), gomock.Any(), common.HexToAddress(bob), big.NewInt(20), gomock.Any()).Return(bobTx, nil),
+	)
+	mockDatabase.EXPECT().
+		SaveTransaction(gomock.Any()).
+		DoAndReturn(func(tx *models.Transaction) error {
+			require.Equal(t, uint64(3), *tx.BatchID)
+			require.Equal(t, models.StatusQueued, tx.Status)
+			return nil
+		}).
+		Times(2)
+
+	resp = post(fmt.Sprintf(`[{"receiver":"%s","amount":"10"},{"receiver":"%s","amount":"20"}]`, alice, bob))
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	response := &SubmitBatchResponse{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(response))
+	require.Equal(t, uint64(3), response.BatchID)
+	require.Len(t, response.Items, 2)
+	require.Equal(t, aliceTx.Hash().Hex(), response.Items[0].Hash)
+	require.Equal(t, bobTx.Hash().Hex(), response.Items[1].Hash)
+}
+
+func TestGetBatch(t *testing.T) {
+	logger := zap.NewNop()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	srv := NewServer(mockClient, mockDatabase, nil, nil, logger)
+
+	mockDatabase.EXPECT().GetBatch(gomock.Any(), uint64(3)).Return(&models.Batch{ID: 3, Token: models.TokenFIL}, nil)
+	mockDatabase.EXPECT().
+		GetBatchTransactions(gomock.Any(), uint64(3)).
+		Return([]models.Transaction{
+			{Hash: "0x01", Status: models.StatusConfirmed},
+			{Hash: "0x02", Status: models.StatusConfirmed},
+			{Hash: "0x03", Status: models.StatusPending},
+		}, nil)
+
+	testServer := httptest.NewServer(srv.e)
+	defer testServer.Close()
+
+	resp, err := http.Get(testServer.URL + "/transactions/batch/3")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	response := &BatchResponse{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(response))
+	require.Equal(t, 3, response.Total)
+	require.Equal(t, 2, response.Statuses[models.StatusConfirmed])
+	require.Equal(t, 1, response.Statuses[models.StatusPending])
+}
+
+func TestSpeedUpTransaction_BatchMember(t *testing.T) {
+	const (
+		originalHash = "0x15e54e2f6e60be523a4ef44e3dc3ab7245bdc98d8b007bfcf1628a320983384b"
+		receiver     = "0xa512eb36e162bfb0e9f55b56bc2a070ca0d3ecd7"
+	)
+
+	logger := zap.NewNop()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	srv := NewServer(mockClient, mockDatabase, nil, nil, logger)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sender := strings.ToLower(crypto.PubkeyToAddress(key.PublicKey).Hex())
+
+	batchID := uint64(3)
+	nonce := uint64(1)
+	original := &models.Transaction{Hash: originalHash, Sender: sender, Receiver: receiver, Status: models.StatusPending, Nonce: &nonce, BatchID: &batchID}
+	mockDatabase.EXPECT().GetTransaction(gomock.Any(), originalHash).Return(original, nil)
+
+	replacementTx := types.NewTx(&types.DynamicFeeTx{Nonce: nonce, GasFeeCap: big.NewInt(2)})
+	mockClient.EXPECT().SpeedUpTransaction(gomock.Any(), gomock.Any(), common.HexToHash(originalHash), gomock.Any()).Return(replacementTx, nil)
+
+	var replacement *models.Transaction
+	mockDatabase.EXPECT().
+		SaveTransaction(gomock.Any()).
+		DoAndReturn(func(tx *models.Transaction) error {
+			require.Equal(t, batchID, *tx.BatchID)
+			replacement = tx
+			return nil
+		})
+
+	testServer := httptest.NewServer(srv.e)
+	defer testServer.Close()
+
+	reqBody := fmt.Sprintf(`{"private_key_hex":"%x"}`, crypto.FromECDSA(key))
+	resp, err := http.Post(testServer.URL+"/transaction/"+originalHash+"/speedup", "application/json", strings.NewReader(reqBody))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	// the batch reports the transfer once, by the replacement while both are pending
+	// and by the mined one afterwards
+	for _, statuses := range [][2]models.TransactionStatus{
+		{models.StatusPending, models.StatusPending},
+		{models.StatusReplaced, models.StatusConfirmed},
+		{models.StatusConfirmed, models.StatusReplaced},
+	} {
+		minedOriginal, minedReplacement := *original, *replacement
+		minedOriginal.Status, minedReplacement.Status = statuses[0], statuses[1]
+
+		mockDatabase.EXPECT().GetBatch(gomock.Any(), batchID).Return(&models.Batch{ID: batchID, Token: models.TokenFIL}, nil)
+		mockDatabase.EXPECT().GetBatchTransactions(gomock.Any(), batchID).Return([]models.Transaction{minedOriginal, minedReplacement}, nil)
+
+		resp, err = http.Get(testServer.URL + "/transactions/batch/3")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		response := &BatchResponse{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(response))
+		require.Equal(t, 1, response.Total)
+		require.Len(t, response.Items, 1)
+		if statuses[0] == models.StatusConfirmed {
+			require.Equal(t, originalHash, response.Items[0].Hash)
+		} else {
+			require.Equal(t, replacementTx.Hash().Hex(), response.Items[0].Hash)
+		}
+		require.Equal(t, map[models.TransactionStatus]int{response.Items[0].Status: 1}, response.Statuses)
+	}
+}
+
+func TestCreateSchedule(t *testing.T) {
+	const receiver = "0xa512eb36e162bfb0e9f55b56bc2a070ca0d3ecd7"
+
+	logger := zap.NewNop()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+	mockWallet := walletmock.NewMockWallet(ctrl)
+
+	srv := NewServer(mockClient, mockDatabase, mockWallet, nil, logger)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+
+	mockWallet.EXPECT().Signer(sender).Return(blockchain.NewLocalSigner(key), nil).AnyTimes()
+	mockDatabase.EXPECT().
+		CreateSchedule(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, schedule *models.Schedule) error {
+			require.Equal(t, strings.ToLower(sender.Hex()), schedule.Sender)
+			require.Equal(t, receiver, schedule.Receiver)
+			require.Equal(t, "@monthly", *schedule.Cron)
+			require.Equal(t, 1, schedule.NextRunAt.Day())
+			require.Equal(t, models.ScheduleActive, schedule.Status)
+			schedule.ID = 5
+			return nil
+		})
+
+	testServer := httptest.NewServer(srv.e)
+	defer testServer.Close()
+
+	post := func(timing string) *http.Response {
+		reqBody := fmt.Sprintf(`{"from":"%s","receiver":"%s","amount":"1000",%s}`, sender.Hex(), receiver, timing)
+		resp, err := http.Post(testServer.URL+"/schedules", "application/json", strings.NewReader(reqBody))
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := post(`"cron":"@monthly","run_at":"2030-01-01T00:00:00Z"`)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp.Body.Close()
+
+	resp = post(`"run_at":"2020-01-01T00:00:00Z"`)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp.Body.Close()
+
+	resp = post(`"cron":"@monthly"`)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	response := &ScheduleResponse{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(response))
+	require.Equal(t, uint64(5), response.ID)
+}
+
+func TestSubmitTransaction_Metadata(t *testing.T) {
+	const receiver = "0xa512eb36e162bfb0e9f55b56bc2a070ca0d3ecd7"
+
+	logger := zap.NewNop()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	srv := NewServer(mockClient, mockDatabase, nil, nil, logger)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	submittedTx := types.NewTx(&types.DynamicFeeTx{Nonce: 1})
+	mockClient.EXPECT().
+		SignFILTransaction(gomock.Any(), gomock.Any(), common.HexToAddress(receiver), big.NewInt(5000), gomock.Any()).
+		Return(submittedTx, nil)
+	mockDatabase.EXPECT().
+		SaveTransaction(gomock.Any()).
+		DoAndReturn(func(tx *models.Transaction) error {
+			require.Equal(t, "April salary", *tx.Memo)
+			require.Equal(t, []string{"payroll", "april"}, []string(tx.Labels))
+			require.Equal(t, "INV-2025-0042", *tx.Reference)
+			return nil
+		})
+
+	testServer := httptest.NewServer(srv.e)
+	defer testServer.Close()
+
+	reqBody := fmt.Sprintf(`{"private_key_hex":"%x","receiver":"%s","amount":"5000","memo":"April salary","labels":["payroll"," april","payroll"],"reference":"INV-2025-0042"}`, crypto.FromECDSA(key), receiver)
+	resp, err := http.Post(testServer.URL+"/transaction/send", "application/json", strings.NewReader(reqBody))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	// labels must not be empty
+	reqBody = fmt.Sprintf(`{"private_key_hex":"%x","receiver":"%s","amount":"5000","labels":[""]}`, crypto.FromECDSA(key), receiver)
+	resp, err = http.Post(testServer.URL+"/transaction/send", "application/json", strings.NewReader(reqBody))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestGetTransactions_Filter(t *testing.T) {
+	const sender = "0xa512eb36e162bfb0e9f55b56bc2a070ca0d3ecd7"
+
+	logger := zap.NewNop()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	srv := NewServer(mockClient, mockDatabase, nil, nil, logger)
+
+	reference := "INV-2025-0042"
+	mockDatabase.EXPECT().
+		GetTransactions(gomock.Any(), database.TransactionFilter{Sender: sender, Label: "payroll", Reference: reference, Direction: models.DirectionOutgoing}).
+		Return([]models.Transaction{{Hash: "0x01", Labels: []string{"payroll"}, Reference: &reference}}, nil)
+
+	testServer := httptest.NewServer(srv.e)
+	defer testServer.Close()
+
+	resp, err := http.Get(testServer.URL + "/transactions/?sender=0xA512eb36e162bfb0e9f55b56bc2a070ca0d3ecd7&label=payroll&reference=INV-2025-0042&direction=outgoing")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var response []models.Transaction
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+	require.Len(t, response, 1)
+	require.Equal(t, reference, *response[0].Reference)
+
+	resp, err = http.Get(testServer.URL + "/transactions/?direction=sideways")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestImportHistory(t *testing.T) {
+	const account = "0x5d5d4d04b70bfe49ad7aac8c4454536070daf180"
+
+	logger := zap.NewNop()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	srv := NewServer(mockClient, mockDatabase, nil, importer.New(logger, mockClient, mockDatabase, nil), logger)
+
+	// resumed from the checkpoint and cut to the per request limit
+	mockDatabase.EXPECT().GetImportCheckpoint(gomock.Any(), account).Return(&models.ImportCheckpoint{Address: account, LastBlock: 999}, nil)
+	mockClient.EXPECT().BlockNumber(gomock.Any()).Return(uint64(10000), nil)
+	mockClient.EXPECT().FindFILTransfers(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+	mockDatabase.EXPECT().ImportTransactions(gomock.Any(), account, gomock.Any(), uint64(1999)).Return(int64(2), nil)
+	mockDatabase.EXPECT().ImportTransactions(gomock.Any(), account, gomock.Any(), uint64(2999)).Return(int64(1), nil)
+
+	testServer := httptest.NewServer(srv.e)
+	defer testServer.Close()
+
+	resp, err := http.Post(testServer.URL+"/admin/import", "application/json", strings.NewReader(`{"address":"0x5D5d4d04B70BFe49ad7Aac8C4454536070dAf180"}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	response := &ImportResponse{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(response))
+	require.Equal(t, ImportResponse{Address: account, FromBlock: 1000, ToBlock: 2999, Imported: 3, Complete: false}, *response)
+}
+
+func TestWatchedAddresses(t *testing.T) {
+	const account = "0x5d5d4d04b70bfe49ad7aac8c4454536070daf180"
+
+	logger := zap.NewNop()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	srv := NewServer(mockClient, mockDatabase, nil, nil, logger)
+
+	label := "customer deposits"
+	mockDatabase.EXPECT().
+		CreateWatchedAddress(gomock.Any(), &models.WatchedAddress{Address: account, Label: &label}).
+		Return(nil)
+	mockDatabase.EXPECT().
+		CreateWatchedAddress(gomock.Any(), gomock.Any()).
+		Return(database.ErrWatchedAddressExists)
+	mockDatabase.EXPECT().DeleteWatchedAddress(gomock.Any(), account).Return(database.ErrWatchedAddressNotFound)
+
+	testServer := httptest.NewServer(srv.e)
+	defer testServer.Close()
+
+	body := `{"address":"0x5D5d4d04B70BFe49ad7Aac8C4454536070dAf180","label":"customer deposits"}`
+	resp, err := http.Post(testServer.URL+"/watched-addresses", "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	response := &WatchedAddressResponse{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(response))
+	require.Equal(t, account, response.Address)
+	require.Equal(t, label, *response.Label)
+
+	resp, err = http.Post(testServer.URL+"/watched-addresses", "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	req, err := http.NewRequest(http.MethodDelete, testServer.URL+"/watched-addresses/"+account, nil)
+	require.NoError(t, err)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestSubmitTransaction_RegisteredToken(t *testing.T) {
+	const (
+		receiver = "0xa512eb36e162bfb0e9f55b56bc2a070ca0d3ecd7"
+		usdfc    = "0xb3042734b608a1b16e9e86b374a3f3e389b4cdf0"
+	)
+
+	logger := zap.NewNop()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	srv := NewServer(mockClient, mockDatabase, nil, nil, logger)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	mockDatabase.EXPECT().
+		GetRegisteredToken(gomock.Any(), "usdfc").
+		Return(&models.RegisteredToken{Address: usdfc, Symbol: "USDFC", Decimals: 6}, nil)
+	submittedTx := types.NewTx(&types.DynamicFeeTx{Nonce: 1})
+	mockClient.EXPECT().
+		SignTokenTransaction(gomock.Any(), gomock.Any(), common.HexToAddress(usdfc), common.HexToAddress(receiver), big.NewInt(5000), gomock.Any()).
+		Return(submittedTx, nil)
+	mockDatabase.EXPECT().
+		SaveTransaction(gomock.Any()).
+		DoAndReturn(func(tx *models.Transaction) error {
+			require.Equal(t, models.Token("USDFC"), tx.Token)
+			require.Equal(t, usdfc, *tx.TokenAddress)
+			require.Equal(t, "5000", tx.Amount.String())
+			return nil
+		})
+	mockDatabase.EXPECT().GetRegisteredToken(gomock.Any(), "DOGE").Return(nil, database.ErrTokenNotFound)
+
+	testServer := httptest.NewServer(srv.e)
+	defer testServer.Close()
+
+	reqBody := fmt.Sprintf(`{"private_key_hex":"%x","receiver":"%s","amount":"5000","token":"usdfc"}`, crypto.FromECDSA(key), receiver)
+	resp, err := http.Post(testServer.URL+"/transaction/send", "application/json", strings.NewReader(reqBody))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	reqBody = fmt.Sprintf(`{"private_key_hex":"%x","receiver":"%s","amount":"5000","token":"DOGE"}`, crypto.FromECDSA(key), receiver)
+	resp, err = http.Post(testServer.URL+"/transaction/send", "application/json", strings.NewReader(reqBody))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestRegisterToken(t *testing.T) {
+	const usdfc = "0xb3042734b608a1b16e9e86b374a3f3e389b4cdf0"
+
+	logger := zap.NewNop()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	srv := NewServer(mockClient, mockDatabase, nil, nil, logger)
+
+	// decimals are read from the contract if omitted
+	mockClient.EXPECT().TokenBalance(gomock.Any(), common.HexToAddress(usdfc), common.Address{}).Return(big.NewInt(0), nil).Times(3)
+	mockClient.EXPECT().TokenDecimals(gomock.Any(), common.HexToAddress(usdfc)).Return(uint8(6), nil).Times(2)
+	mockDatabase.EXPECT().
+		CreateRegisteredToken(gomock.Any(), &models.RegisteredToken{Address: usdfc, Symbol: "USDFC", Decimals: 6}).
+		Return(nil)
+
+	testServer := httptest.NewServer(srv.e)
+	defer testServer.Close()
+
+	resp, err := http.Post(testServer.URL+"/tokens", "application/json", strings.NewReader(`{"address":"0xB3042734b608a1B16e9e86B374A3f3e389B4cDf0","symbol":"USDFC"}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	response := &TokenResponse{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(response))
+	require.Equal(t, uint8(6), response.Decimals)
+
+	// given decimals are checked against the contract
+	resp, err = http.Post(testServer.URL+"/tokens", "application/json", strings.NewReader(`{"address":"0xB3042734b608a1B16e9e86B374A3f3e389B4cDf0","symbol":"USDFC","decimals":18}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	// and used as they are if the contract has no decimals()
+	mockClient.EXPECT().TokenDecimals(gomock.Any(), common.HexToAddress(usdfc)).Return(uint8(0), errors.New("execution reverted"))
+	mockDatabase.EXPECT().
+		CreateRegisteredToken(gomock.Any(), &models.RegisteredToken{Address: usdfc, Symbol: "USDFC", Decimals: 18}).
+		Return(nil)
+
+	resp, err = http.Post(testServer.URL+"/tokens", "application/json", strings.NewReader(`{"address":"0xB3042734b608a1B16e9e86B374A3f3e389B4cDf0","symbol":"USDFC","decimals":18}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	// a contract without balanceOf is rejected even with decimals
+	mockClient.EXPECT().TokenBalance(gomock.Any(), common.HexToAddress(usdfc), common.Address{}).Return(nil, errors.New("execution reverted"))
+
+	resp, err = http.Post(testServer.URL+"/tokens", "application/json", strings.NewReader(`{"address":"0xB3042734b608a1B16e9e86B374A3f3e389B4cDf0","symbol":"USDFC","decimals":18}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	// built-in symbols cannot be registered
+	resp, err = http.Post(testServer.URL+"/tokens", "application/json", strings.NewReader(`{"address":"0xB3042734b608a1B16e9e86B374A3f3e389B4cDf0","symbol":"ifil","decimals":18}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestDepositToPool(t *testing.T) {
+	const pool = "0xe764acf02d8b7c21d2b6a8f0a96c78541e0dc3fd"
+
+	logger := zap.NewNop()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	poolAddress := common.HexToAddress(pool)
+	srv := NewServer(mockClient, mockDatabase, nil, nil, logger)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	amount, _ := new(big.Int).SetString("1000000000000000000", 10)
+	expected, _ := new(big.Int).SetString("950000000000000000", 10)
+	mockClient.EXPECT().PreviewDeposit(gomock.Any(), amount).Return(expected, nil)
+	submittedTx := types.NewTx(&types.DynamicFeeTx{Nonce: 1, To: &poolAddress, Value: amount})
+	mockClient.EXPECT().SignPoolDeposit(gomock.Any(), gomock.Any(), amount, gomock.Any()).Return(submittedTx, nil)
+	mockDatabase.EXPECT().
+		SaveTransaction(gomock.Any()).
+		DoAndReturn(func(tx *models.Transaction) error {
+			require.Equal(t, models.KindDeposit, tx.Kind)
+			require.Equal(t, models.TokenFIL, tx.Token)
+			require.Equal(t, pool, tx.Receiver)
+			require.Equal(t, models.StatusQueued, tx.Status)
+			require.Equal(t, expected.String(), tx.ExpectedAmount.String())
+			require.Equal(t, models.TokenIFIL, *tx.ExpectedToken)
+			return nil
+		})
+
+	testServer := httptest.NewServer(srv.e)
+	defer testServer.Close()
+
+	reqBody := fmt.Sprintf(`{"private_key_hex":"%x","amount":"1000000000000000000"}`, crypto.FromECDSA(key))
+	resp, err := http.Post(testServer.URL+"/pool/deposit", "application/json", strings.NewReader(reqBody))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	response := &PoolDepositResponse{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(response))
+	require.Equal(t, submittedTx.Hash().String(), response.Hash)
+	require.Equal(t, expected.String(), response.ExpectedIFIL)
+}
+
+func TestWithdrawFromPool(t *testing.T) {
+	const (
+		pool = "0xe764acf02d8b7c21d2b6a8f0a96c78541e0dc3fd"
+		ifil = "0x690908f7fa93afc040cfbd9fe1ddd2c2668aa0e0"
+	)
+
+	logger := zap.NewNop()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	poolAddress := common.HexToAddress(pool)
+	srv := NewServer(mockClient, mockDatabase, nil, nil, logger)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+
+	shares := big.NewInt(1000)
+	assets := big.NewInt(1050)
+	ifilAddress := common.HexToAddress(ifil)
+	approvalTx := types.NewTx(&types.DynamicFeeTx{Nonce: 1, To: &ifilAddress})
+	exitTx := types.NewTx(&types.DynamicFeeTx{Nonce: 2, To: &poolAddress})
+
+	// redeeming iFIL without allowance only queues an approval, the withdrawal
+	// cannot be estimated before the allowance is set
+	exit := blockchain.PoolExit{Redeem: true, Amount: shares}
+	mockClient.EXPECT().PreviewPoolExit(gomock.Any(), exit).Return(shares, assets, nil).Times(3)
+	gomock.InOrder(
+		mockClient.EXPECT().PoolAllowance(gomock.Any(), sender).Return(big.NewInt(0), nil).Times(2),
+		mockClient.EXPECT().PoolAllowance(gomock.Any(), sender).Return(shares, nil),
+	)
+	mockDatabase.EXPECT().
+		GetOpenApproval(gomock.Any(), strings.ToLower(sender.Hex()), decimal.NewFromBigInt(shares, 0)).
+		Return(nil, database.ErrTxNotFound)
+	mockClient.EXPECT().SignPoolApproval(gomock.Any(), gomock.Any(), shares, gomock.Any()).Return(approvalTx, nil)
+	mockDatabase.EXPECT().
+		SaveTransaction(gomock.Any()).
+		DoAndReturn(func(tx *models.Transaction) error {
+			require.Equal(t, models.KindApproval, tx.Kind)
+			require.Equal(t, ifil, tx.Receiver)
+			require.Equal(t, models.TokenIFIL, tx.Token)
+			require.Nil(t, tx.ExpectedAmount)
+			return nil
+		})
+
+	testServer := httptest.NewServer(srv.e)
+	defer testServer.Close()
+
+	reqBody := fmt.Sprintf(`{"private_key_hex":"%x","ifil_amount":"1000"}`, crypto.FromECDSA(key))
+	resp, err := http.Post(testServer.URL+"/pool/withdraw", "application/json", strings.NewReader(reqBody))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	response := &PoolWithdrawResponse{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(response))
+	approvalHash := approvalTx.Hash().String()
+	require.Equal(t, PoolWithdrawResponse{ApprovalHash: &approvalHash, IFIL: "1000", ExpectedFIL: "1050"}, *response)
+
+	// a retry before the approval is mined gets the same approval
+	mockDatabase.EXPECT().
+		GetOpenApproval(gomock.Any(), strings.ToLower(sender.Hex()), decimal.NewFromBigInt(shares, 0)).
+		Return(&models.Transaction{Hash: approvalHash, Kind: models.KindApproval, Status: models.StatusPending}, nil)
+
+	resp, err = http.Post(testServer.URL+"/pool/withdraw", "application/json", strings.NewReader(reqBody))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	response = &PoolWithdrawResponse{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(response))
+	require.Equal(t, approvalHash, *response.ApprovalHash)
+	require.Empty(t, response.Hash)
+
+	// once the approval is mined the same request queues the withdrawal
+	mockClient.EXPECT().SignPoolExit(gomock.Any(), gomock.Any(), exit, gomock.Any()).Return(exitTx, nil)
+	mockDatabase.EXPECT().
+		SaveTransaction(gomock.Any()).
+		DoAndReturn(func(tx *models.Transaction) error {
+			require.Equal(t, models.KindWithdrawal, tx.Kind)
+			require.Equal(t, pool, tx.Receiver)
+			require.Equal(t, "1000", tx.Amount.String())
+			require.Equal(t, models.TokenIFIL, tx.Token)
+			require.Equal(t, "1050", tx.ExpectedAmount.String())
+			require.Equal(t, models.TokenFIL, *tx.ExpectedToken)
+			return nil
+		})
+
+	resp, err = http.Post(testServer.URL+"/pool/withdraw", "application/json", strings.NewReader(reqBody))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	response = &PoolWithdrawResponse{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(response))
+	require.Equal(t, exitTx.Hash().String(), response.Hash)
+	require.Nil(t, response.ApprovalHash)
+
+	// exactly one amount must be given
+	reqBody = fmt.Sprintf(`{"private_key_hex":"%x","ifil_amount":"1000","fil_amount":"1050"}`, crypto.FromECDSA(key))
+	resp, err = http.Post(testServer.URL+"/pool/withdraw", "application/json", strings.NewReader(reqBody))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
@@ -0,0 +1,188 @@
+package server
+
+import (
+	"app/internal/database"
+	"app/internal/database/models"
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+var tokenSymbolPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,32}$`)
+
+// transferToken is the token of a transfer. Contract is set for registered ERC-20
+// tokens, FIL and iFIL are built in.
+type transferToken struct {
+	symbol   models.Token
+	contract *common.Address
+}
+
+var filToken = transferToken{symbol: models.TokenFIL}
+
+// storedToken returns the token of a stored transfer.
+func storedToken(symbol models.Token, address *string) transferToken {
+	token := transferToken{symbol: symbol}
+	if address != nil {
+		contract := common.HexToAddress(*address)
+		token.contract = &contract
+	}
+	return token
+}
+
+// address is the contract address as stored with transfers.
+func (t transferToken) address() *string {
+	if t.contract == nil {
+		return nil
+	}
+	address := strings.ToLower(t.contract.Hex())
+	return &address
+}
+
+// resolveToken defaults to FIL when no token is given. Symbols other than FIL and
+// iFIL must be registered, they are matched case-insensitively.
+func (s *Server) resolveToken(ctx context.Context, symbol string) (transferToken, error) {
+	switch {
+	case symbol == "" || strings.EqualFold(symbol, string(models.TokenFIL)):
+		return filToken, nil
+	case strings.EqualFold(symbol, string(models.TokenIFIL)):
+		return transferToken{symbol: models.TokenIFIL}, nil
+	}
+
+	registered, err := s.db.GetRegisteredToken(ctx, symbol)
+	if err != nil {
+		if errors.Is(err, database.ErrTokenNotFound) {
+			s.logger.Warn("Invalid token", zap.String("token", symbol))
+			return transferToken{}, ErrInvalidToken
+		}
+		s.logger.Error("Failed to get registered token", zap.String("token", symbol), zap.Error(err))
+		return transferToken{}, echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to get registered token"))
+	}
+	return storedToken(registered.Symbol, &registered.Address), nil
+}
+
+// registerToken adds an ERC-20 token to the registry. The contract must implement
+// balanceOf, decimals are read from the contract unless given. Given decimals must
+// match the contract if it implements decimals().
+func (s *Server) registerToken(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req TokenRequest
+	if err := c.Bind(&req); err != nil {
+		s.logger.Warn("Invalid request body", zap.Error(err))
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request format")
+	}
+	if !isValidAddress(req.Address) {
+		return ErrInvalidAddress
+	}
+	if !tokenSymbolPattern.MatchString(req.Symbol) ||
+		strings.EqualFold(req.Symbol, string(models.TokenFIL)) ||
+		strings.EqualFold(req.Symbol, string(models.TokenIFIL)) {
+		return ErrInvalidTokenSymbol
+	}
+
+	contract := common.HexToAddress(strings.TrimSpace(req.Address))
+	if _, err := s.bc.TokenBalance(ctx, contract, common.Address{}); err != nil {
+		s.logger.Warn("Failed to read token balance", zap.String("address", contract.Hex()), zap.Error(err))
+		return ErrInvalidTokenContract
+	}
+
+	// decimals() is optional in ERC-20, so it may only be missing if decimals are given
+	decimals := req.Decimals
+	read, err := s.bc.TokenDecimals(ctx, contract)
+	switch {
+	case err != nil && decimals == nil:
+		s.logger.Warn("Failed to read token decimals", zap.String("address", contract.Hex()), zap.Error(err))
+		return ErrInvalidTokenContract
+	case err != nil:
+		s.logger.Info("Token contract has no decimals, using the given ones", zap.String("address", contract.Hex()), zap.Error(err))
+	case decimals == nil:
+		decimals = &read
+	case *decimals != read:
+		s.logger.Warn("Given token decimals differ from the contract", zap.String("address", contract.Hex()), zap.Uint8("decimals", read))
+		return ErrTokenDecimalsMismatch
+	}
+
+	token := &models.RegisteredToken{
+		Address:  strings.ToLower(contract.Hex()),
+		Symbol:   models.Token(req.Symbol),
+		Decimals: *decimals,
+	}
+	if err := s.db.CreateRegisteredToken(ctx, token); err != nil {
+		if errors.Is(err, database.ErrTokenExists) {
+			return ErrTokenExists
+		}
+		s.logger.Error("Failed to register token", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to register token"))
+	}
+
+	s.logger.Info("Token registered", zap.String("symbol", req.Symbol), zap.String("address", token.Address))
+	return c.JSON(http.StatusCreated, newTokenResponse(token))
+}
+
+func (s *Server) listTokens(c echo.Context) error {
+	tokens, err := s.db.GetRegisteredTokens(c.Request().Context())
+	if err != nil {
+		s.logger.Error("Failed to list tokens", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to list tokens"))
+	}
+
+	response := make([]*TokenResponse, 0, len(tokens))
+	for i := range tokens {
+		response = append(response, newTokenResponse(&tokens[i]))
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// unregisterToken removes a token from the registry. Stored transfers and schedules
+// keep its contract address.
+func (s *Server) unregisterToken(c echo.Context) error {
+	symbol := c.Param("symbol")
+	if err := s.db.DeleteRegisteredToken(c.Request().Context(), symbol); err != nil {
+		if errors.Is(err, database.ErrTokenNotFound) {
+			return ErrTokenNotFound
+		}
+		s.logger.Error("Failed to unregister token", zap.String("symbol", symbol), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to unregister token"))
+	}
+
+	s.logger.Info("Token unregistered", zap.String("symbol", symbol))
+	return c.NoContent(http.StatusNoContent)
+}
+
+// tokenBalances returns the balances of address for every registered token. A token
+// whose contract fails reports an error instead, the other balances are still returned.
+func (s *Server) tokenBalances(ctx context.Context, address common.Address) ([]TokenBalanceResponse, error) {
+	tokens, err := s.db.GetRegisteredTokens(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list tokens")
+	}
+
+	balances := make([]TokenBalanceResponse, 0, len(tokens))
+	for _, token := range tokens {
+		response := TokenBalanceResponse{Symbol: token.Symbol, Address: token.Address}
+		balance, err := s.bc.TokenBalance(ctx, common.HexToAddress(token.Address), address)
+		if err != nil {
+			s.logger.Warn("Failed to get token balance", zap.String("symbol", string(token.Symbol)), zap.String("address", address.Hex()), zap.Error(err))
+			response.Error = "failed to get balance"
+		} else {
+			response.Balance = decimal.NewFromBigInt(balance, -int32(token.Decimals)).String()
+		}
+		balances = append(balances, response)
+	}
+	return balances, nil
+}
+
+func newTokenResponse(token *models.RegisteredToken) *TokenResponse {
+	return &TokenResponse{
+		Symbol:    token.Symbol,
+		Address:   token.Address,
+		Decimals:  token.Decimals,
+		CreatedAt: token.CreatedAt,
+	}
+}
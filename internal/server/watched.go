@@ -0,0 +1,130 @@
+package server
+
+import (
+	"app/internal/database"
+	"app/internal/database/models"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+)
+
+func (s *Server) createWatchedAddress(c echo.Context) error {
+	var req WatchedAddressRequest
+	if err := c.Bind(&req); err != nil {
+		s.logger.Warn("Invalid request body", zap.Error(err))
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request format")
+	}
+	if !isValidAddress(req.Address) {
+		return ErrInvalidAddress
+	}
+
+	watched := &models.WatchedAddress{Address: strings.ToLower(strings.TrimSpace(req.Address)), Label: req.Label}
+	if err := s.db.CreateWatchedAddress(c.Request().Context(), watched); err != nil {
+		if errors.Is(err, database.ErrWatchedAddressExists) {
+			return ErrWatchedAddressExists
+		}
+		s.logger.Error("Failed to create watched address", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to create watched address"))
+	}
+
+	s.logger.Info("Watched address created", zap.String("address", watched.Address))
+	return c.JSON(http.StatusCreated, newWatchedAddressResponse(watched))
+}
+
+func (s *Server) listWatchedAddresses(c echo.Context) error {
+	watched, err := s.db.GetWatchedAddresses(c.Request().Context())
+	if err != nil {
+		s.logger.Error("Failed to list watched addresses", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to list watched addresses"))
+	}
+
+	response := make([]*WatchedAddressResponse, 0, len(watched))
+	for i := range watched {
+		response = append(response, newWatchedAddressResponse(&watched[i]))
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+func (s *Server) getWatchedAddress(c echo.Context) error {
+	address, err := watchedAddressParam(c)
+	if err != nil {
+		return err
+	}
+
+	watched, err := s.db.GetWatchedAddress(c.Request().Context(), address)
+	if err != nil {
+		if errors.Is(err, database.ErrWatchedAddressNotFound) {
+			return ErrWatchedAddressNotFound
+		}
+		s.logger.Error("Failed to get watched address", zap.String("address", address), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to get watched address"))
+	}
+	return c.JSON(http.StatusOK, newWatchedAddressResponse(watched))
+}
+
+// updateWatchedAddress replaces the label of a watched address.
+func (s *Server) updateWatchedAddress(c echo.Context) error {
+	address, err := watchedAddressParam(c)
+	if err != nil {
+		return err
+	}
+
+	var req WatchedAddressRequest
+	if err := c.Bind(&req); err != nil {
+		s.logger.Warn("Invalid request body", zap.Error(err))
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request format")
+	}
+
+	ctx := c.Request().Context()
+	if err := s.db.UpdateWatchedAddress(ctx, &models.WatchedAddress{Address: address, Label: req.Label}); err != nil {
+		if errors.Is(err, database.ErrWatchedAddressNotFound) {
+			return ErrWatchedAddressNotFound
+		}
+		s.logger.Error("Failed to update watched address", zap.String("address", address), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to update watched address"))
+	}
+
+	watched, err := s.db.GetWatchedAddress(ctx, address)
+	if err != nil {
+		s.logger.Error("Failed to get watched address", zap.String("address", address), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to get watched address"))
+	}
+	return c.JSON(http.StatusOK, newWatchedAddressResponse(watched))
+}
+
+// deleteWatchedAddress stops recording incoming transfers, recorded ones are kept.
+func (s *Server) deleteWatchedAddress(c echo.Context) error {
+	address, err := watchedAddressParam(c)
+	if err != nil {
+		return err
+	}
+
+	if err := s.db.DeleteWatchedAddress(c.Request().Context(), address); err != nil {
+		if errors.Is(err, database.ErrWatchedAddressNotFound) {
+			return ErrWatchedAddressNotFound
+		}
+		s.logger.Error("Failed to delete watched address", zap.String("address", address), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, "failed to delete watched address"))
+	}
+
+	s.logger.Info("Watched address deleted", zap.String("address", address))
+	return c.NoContent(http.StatusNoContent)
+}
+
+func watchedAddressParam(c echo.Context) (string, error) {
+	address := c.Param("address")
+	if !isValidAddress(address) {
+		return "", ErrInvalidAddress
+	}
+	return strings.ToLower(address), nil
+}
+
+func newWatchedAddressResponse(watched *models.WatchedAddress) *WatchedAddressResponse {
+	return &WatchedAddressResponse{
+		Address:   watched.Address,
+		Label:     watched.Label,
+		CreatedAt: watched.CreatedAt,
+	}
+}
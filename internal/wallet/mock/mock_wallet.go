@@ -0,0 +1,118 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/wallet/wallet.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/wallet/wallet.go -destination=internal/wallet/mock/mock_wallet.go -package=wallet
+//
+
+// Package wallet is a generated GoMock package.
+package wallet
+
+import (
+	blockchain "app/internal/blockchain"
+	ecdsa "crypto/ecdsa"
+	reflect "reflect"
+
+	common "github.com/ethereum/go-ethereum/common"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockWallet is a mock of Wallet interface.
+type MockWallet struct {
+	ctrl     *gomock.Controller
+	recorder *MockWalletMockRecorder
+	isgomock struct{}
+}
+
+// MockWalletMockRecorder is the mock recorder for MockWallet.
+type MockWalletMockRecorder struct {
+	mock *MockWallet
+}
+
+// NewMockWallet creates a new mock instance.
+func NewMockWallet(ctrl *gomock.Controller) *MockWallet {
+	mock := &MockWallet{ctrl: ctrl}
+	mock.recorder = &MockWalletMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWallet) EXPECT() *MockWalletMockRecorder {
+	return m.recorder
+}
+
+// Accounts mocks base method.
+func (m *MockWallet) Accounts() ([]common.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Accounts")
+	ret0, _ := ret[0].([]common.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Accounts indicates an expected call of Accounts.
+func (mr *MockWalletMockRecorder) Accounts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Accounts", reflect.TypeOf((*MockWallet)(nil).Accounts))
+}
+
+// ImportJSON mocks base method.
+func (m *MockWallet) ImportJSON(keyJSON []byte, passphrase string) (common.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportJSON", keyJSON, passphrase)
+	ret0, _ := ret[0].(common.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportJSON indicates an expected call of ImportJSON.
+func (mr *MockWalletMockRecorder) ImportJSON(keyJSON, passphrase any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportJSON", reflect.TypeOf((*MockWallet)(nil).ImportJSON), keyJSON, passphrase)
+}
+
+// ImportKey mocks base method.
+func (m *MockWallet) ImportKey(key *ecdsa.PrivateKey) (common.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportKey", key)
+	ret0, _ := ret[0].(common.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportKey indicates an expected call of ImportKey.
+func (mr *MockWalletMockRecorder) ImportKey(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportKey", reflect.TypeOf((*MockWallet)(nil).ImportKey), key)
+}
+
+// NewAccount mocks base method.
+func (m *MockWallet) NewAccount() (common.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewAccount")
+	ret0, _ := ret[0].(common.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewAccount indicates an expected call of NewAccount.
+func (mr *MockWalletMockRecorder) NewAccount() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewAccount", reflect.TypeOf((*MockWallet)(nil).NewAccount))
+}
+
+// Signer mocks base method.
+func (m *MockWallet) Signer(address common.Address) (blockchain.Signer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Signer", address)
+	ret0, _ := ret[0].(blockchain.Signer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Signer indicates an expected call of Signer.
+func (mr *MockWalletMockRecorder) Signer(address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Signer", reflect.TypeOf((*MockWallet)(nil).Signer), address)
+}
@@ -0,0 +1,190 @@
+package wallet
+
+import (
+	"app/internal/blockchain"
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"math/big"
+	"time"
+)
+
+const DefaultRemoteTimeout = 60 * time.Second
+
+var (
+	ErrSignerRejected = errors.New("remote signer rejected the request")
+	ErrSignerTimeout  = errors.New("remote signer did not respond in time")
+)
+
+// SignTxArgs mirrors the transaction arguments of Clef's account_signTransaction.
+type SignTxArgs struct {
+	From                 common.MixedcaseAddress  `json:"from"`
+	To                   *common.MixedcaseAddress `json:"to"`
+	Gas                  hexutil.Uint64           `json:"gas"`
+	GasPrice             *hexutil.Big             `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big             `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big             `json:"maxPriorityFeePerGas,omitempty"`
+	Value                hexutil.Big              `json:"value"`
+	Nonce                hexutil.Uint64           `json:"nonce"`
+	Data                 *hexutil.Bytes           `json:"data,omitempty"`
+	ChainID              *hexutil.Big             `json:"chainId,omitempty"`
+}
+
+type SignTxResult struct {
+	Raw hexutil.Bytes      `json:"raw"`
+	Tx  *types.Transaction `json:"tx"`
+}
+
+type remoteWallet struct {
+	logger  *zap.Logger
+	rpc     *rpc.Client
+	timeout time.Duration
+}
+
+// NewRemote connects to an external signer speaking Clef's account_* JSON-RPC API.
+// endpoint is either an http(s) URL or a path to a Unix socket.
+func NewRemote(logger *zap.Logger, endpoint string, timeout time.Duration) (Wallet, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := rpc.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to remote signer: %w", err)
+	}
+
+	logger.Info("Remote signer connected", zap.String("endpoint", endpoint))
+	return &remoteWallet{
+		logger:  logger,
+		rpc:     client,
+		timeout: timeout,
+	}, nil
+}
+
+func (w *remoteWallet) Accounts() ([]common.Address, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+	defer cancel()
+
+	var addresses []common.Address
+	if err := w.call(ctx, &addresses, "account_list"); err != nil {
+		return nil, err
+	}
+	return addresses, nil
+}
+
+func (w *remoteWallet) NewAccount() (common.Address, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+	defer cancel()
+
+	var address common.Address
+	if err := w.call(ctx, &address, "account_new"); err != nil {
+		return common.Address{}, err
+	}
+	return address, nil
+}
+
+func (w *remoteWallet) ImportKey(*ecdsa.PrivateKey) (common.Address, error) {
+	return common.Address{}, ErrUnsupported
+}
+
+func (w *remoteWallet) ImportJSON([]byte, string) (common.Address, error) {
+	return common.Address{}, ErrUnsupported
+}
+
+func (w *remoteWallet) Signer(address common.Address) (blockchain.Signer, error) {
+	addresses, err := w.Accounts()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, known := range addresses {
+		if known == address {
+			return &remoteSigner{wallet: w, address: address}, nil
+		}
+	}
+	return nil, ErrAccountNotFound
+}
+
+func (w *remoteWallet) call(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	err := w.rpc.CallContext(ctx, result, method, args...)
+	if err == nil {
+		return nil
+	}
+
+	w.logger.Error("remote signer call failed", zap.String("method", method), zap.Error(err))
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %s", ErrSignerTimeout, method)
+	}
+
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return fmt.Errorf("%w: %s", ErrSignerRejected, rpcErr.Error())
+	}
+	return fmt.Errorf("remote signer call %s failed: %w", method, err)
+}
+
+type remoteSigner struct {
+	wallet  *remoteWallet
+	address common.Address
+}
+
+func (s *remoteSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *remoteSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.wallet.timeout)
+	defer cancel()
+
+	args := SignTxArgs{
+		From:    common.NewMixedcaseAddress(s.address),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   hexutil.Big(*tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		ChainID: (*hexutil.Big)(chainID),
+	}
+	if tx.To() != nil {
+		to := common.NewMixedcaseAddress(*tx.To())
+		args.To = &to
+	}
+	if len(tx.Data()) > 0 {
+		data := hexutil.Bytes(tx.Data())
+		args.Data = &data
+	}
+	if tx.Type() == types.DynamicFeeTxType {
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+	} else {
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	}
+
+	var result SignTxResult
+	if err := s.wallet.call(ctx, &result, "account_signTransaction", args); err != nil {
+		return nil, err
+	}
+
+	signedTx := new(types.Transaction)
+	if err := signedTx.UnmarshalBinary(result.Raw); err != nil {
+		return nil, fmt.Errorf("failed to decode remotely signed tx: %w", err)
+	}
+
+	txSigner := types.LatestSignerForChainID(chainID)
+	sender, err := types.Sender(txSigner, signedTx)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote signature: %w", err)
+	}
+	if sender != s.address || txSigner.Hash(signedTx) != txSigner.Hash(tx) {
+		return nil, errors.New("remote signer returned a different transaction than requested")
+	}
+	return signedTx, nil
+}
+
+// SignHash is not part of Clef's API: it only signs structured data it can show to the operator.
+func (s *remoteSigner) SignHash(context.Context, common.Hash) ([]byte, error) {
+	return nil, ErrUnsupported
+}
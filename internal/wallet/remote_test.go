@@ -0,0 +1,75 @@
+package wallet
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRemoteWallet(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
+
+	local, err := newKeystoreWallet(logger, t.TempDir(), "secret", keystore.LightScryptN, keystore.LightScryptP)
+	require.NoError(t, err)
+	account, err := local.NewAccount()
+	require.NoError(t, err)
+
+	rpcSrv := rpc.NewServer()
+	defer rpcSrv.Stop()
+	require.NoError(t, rpcSrv.RegisterName("account", NewSignerService(logger, local, big.NewInt(1000))))
+
+	httpSrv := httptest.NewServer(rpcSrv)
+	defer httpSrv.Close()
+
+	remote, err := NewRemote(logger, httpSrv.URL, 5*time.Second)
+	require.NoError(t, err)
+
+	addresses, err := remote.Accounts()
+	require.NoError(t, err)
+	require.Equal(t, []common.Address{account}, addresses)
+
+	_, err = remote.Signer(common.HexToAddress("0xa512eb36e162bfb0e9f55b56bc2a070ca0d3ecd7"))
+	require.ErrorIs(t, err, ErrAccountNotFound)
+
+	signer, err := remote.Signer(account)
+	require.NoError(t, err)
+
+	chainID := big.NewInt(314159)
+	receiver := common.HexToAddress("0xa512eb36e162bfb0e9f55b56bc2a070ca0d3ecd7")
+	newTx := func(value int64) *types.Transaction {
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     5,
+			GasTipCap: big.NewInt(1e9),
+			GasFeeCap: big.NewInt(3e9),
+			Gas:       21000,
+			To:        &receiver,
+			Value:     big.NewInt(value),
+		})
+	}
+
+	tx := newTx(1000)
+	signedTx, err := signer.SignTx(ctx, tx, chainID)
+	require.NoError(t, err)
+
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signedTx)
+	require.NoError(t, err)
+	require.Equal(t, account, sender)
+	require.Equal(t, tx.Nonce(), signedTx.Nonce())
+
+	// stand-in signer denies transfers above its limit
+	_, err = signer.SignTx(ctx, newTx(1001), chainID)
+	require.ErrorIs(t, err, ErrSignerRejected)
+
+	_, err = remote.ImportKey(nil)
+	require.ErrorIs(t, err, ErrUnsupported)
+}
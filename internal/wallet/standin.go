@@ -0,0 +1,105 @@
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"math/big"
+)
+
+var ErrRequestDenied = errors.New("request denied")
+
+// SignerService is a stand-in for Clef serving account_list, account_new and
+// account_signTransaction from a local wallet. It is meant for local development
+// and tests only: requests are approved automatically unless they exceed maxValue.
+type SignerService struct {
+	logger   *zap.Logger
+	wallet   Wallet
+	maxValue *big.Int
+}
+
+// NewSignerService creates the stand-in signer. A nil maxValue approves every transfer.
+func NewSignerService(logger *zap.Logger, w Wallet, maxValue *big.Int) *SignerService {
+	return &SignerService{
+		logger:   logger,
+		wallet:   w,
+		maxValue: maxValue,
+	}
+}
+
+func (s *SignerService) List() ([]common.Address, error) {
+	return s.wallet.Accounts()
+}
+
+func (s *SignerService) New() (common.Address, error) {
+	return s.wallet.NewAccount()
+}
+
+func (s *SignerService) SignTransaction(ctx context.Context, args SignTxArgs) (*SignTxResult, error) {
+	if args.ChainID == nil {
+		return nil, errors.New("chainId is required")
+	}
+
+	value := args.Value.ToInt()
+	if s.maxValue != nil && value.Cmp(s.maxValue) > 0 {
+		s.logger.Warn("Sign request denied", zap.String("from", args.From.Address().Hex()), zap.String("value", value.String()))
+		return nil, ErrRequestDenied
+	}
+
+	signer, err := s.wallet.Signer(args.From.Address())
+	if err != nil {
+		return nil, err
+	}
+
+	var to *common.Address
+	if args.To != nil {
+		address := args.To.Address()
+		to = &address
+	}
+	var data []byte
+	if args.Data != nil {
+		data = *args.Data
+	}
+
+	var tx *types.Transaction
+	switch {
+	case args.MaxFeePerGas != nil && args.MaxPriorityFeePerGas != nil:
+		tx = types.NewTx(&types.DynamicFeeTx{
+			ChainID:   args.ChainID.ToInt(),
+			Nonce:     uint64(args.Nonce),
+			GasTipCap: args.MaxPriorityFeePerGas.ToInt(),
+			GasFeeCap: args.MaxFeePerGas.ToInt(),
+			Gas:       uint64(args.Gas),
+			To:        to,
+			Value:     value,
+			Data:      data,
+		})
+	case args.GasPrice != nil:
+		tx = types.NewTx(&types.LegacyTx{
+			Nonce:    uint64(args.Nonce),
+			GasPrice: args.GasPrice.ToInt(),
+			Gas:      uint64(args.Gas),
+			To:       to,
+			Value:    value,
+			Data:     data,
+		})
+	default:
+		return nil, errors.New("either gasPrice or maxFeePerGas and maxPriorityFeePerGas must be set")
+	}
+
+	signedTx, err := signer.SignTx(ctx, tx, args.ChainID.ToInt())
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := signedTx.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode signed tx: %w", err)
+	}
+
+	s.logger.Info("Transaction signed", zap.String("from", args.From.Address().Hex()), zap.String("hash", signedTx.Hash().Hex()))
+	return &SignTxResult{Raw: raw, Tx: signedTx}, nil
+}
@@ -0,0 +1,136 @@
+package wallet
+
+import (
+	"app/internal/blockchain"
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"math/big"
+)
+
+var (
+	ErrAccountNotFound = errors.New("account not found")
+	ErrAccountExists   = errors.New("account already exists")
+	ErrUnsupported     = errors.New("operation is not supported by this wallet")
+)
+
+type Wallet interface {
+	Accounts() ([]common.Address, error)
+	NewAccount() (common.Address, error)
+	ImportKey(key *ecdsa.PrivateKey) (common.Address, error)
+	ImportJSON(keyJSON []byte, passphrase string) (common.Address, error)
+	Signer(address common.Address) (blockchain.Signer, error)
+}
+
+type keystoreWallet struct {
+	logger     *zap.Logger
+	ks         *keystore.KeyStore
+	passphrase string
+}
+
+// NewKeystore opens (or creates) an encrypted keystore directory and unlocks
+// every account in it with the given passphrase.
+func NewKeystore(logger *zap.Logger, dir, passphrase string) (Wallet, error) {
+	return newKeystoreWallet(logger, dir, passphrase, keystore.StandardScryptN, keystore.StandardScryptP)
+}
+
+func newKeystoreWallet(logger *zap.Logger, dir, passphrase string, scryptN, scryptP int) (Wallet, error) {
+	ks := keystore.NewKeyStore(dir, scryptN, scryptP)
+	for _, account := range ks.Accounts() {
+		if err := ks.Unlock(account, passphrase); err != nil {
+			return nil, fmt.Errorf("failed to unlock account %s: %w", account.Address.Hex(), err)
+		}
+	}
+
+	logger.Info("Keystore opened", zap.String("dir", dir), zap.Int("accounts", len(ks.Accounts())))
+	return &keystoreWallet{
+		logger:     logger,
+		ks:         ks,
+		passphrase: passphrase,
+	}, nil
+}
+
+func (w *keystoreWallet) Accounts() ([]common.Address, error) {
+	ksAccounts := w.ks.Accounts()
+	addresses := make([]common.Address, 0, len(ksAccounts))
+	for _, account := range ksAccounts {
+		addresses = append(addresses, account.Address)
+	}
+	return addresses, nil
+}
+
+func (w *keystoreWallet) NewAccount() (common.Address, error) {
+	account, err := w.ks.NewAccount(w.passphrase)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to create account: %w", err)
+	}
+	return account.Address, w.unlock(account)
+}
+
+func (w *keystoreWallet) ImportKey(key *ecdsa.PrivateKey) (common.Address, error) {
+	account, err := w.ks.ImportECDSA(key, w.passphrase)
+	if err != nil {
+		if errors.Is(err, keystore.ErrAccountAlreadyExists) {
+			return common.Address{}, ErrAccountExists
+		}
+		return common.Address{}, fmt.Errorf("failed to import key: %w", err)
+	}
+	return account.Address, w.unlock(account)
+}
+
+func (w *keystoreWallet) ImportJSON(keyJSON []byte, passphrase string) (common.Address, error) {
+	account, err := w.ks.Import(keyJSON, passphrase, w.passphrase)
+	if err != nil {
+		if errors.Is(err, keystore.ErrAccountAlreadyExists) {
+			return common.Address{}, ErrAccountExists
+		}
+		return common.Address{}, fmt.Errorf("failed to import keystore json: %w", err)
+	}
+	return account.Address, w.unlock(account)
+}
+
+func (w *keystoreWallet) Signer(address common.Address) (blockchain.Signer, error) {
+	account := accounts.Account{Address: address}
+	if !w.ks.HasAddress(address) {
+		return nil, ErrAccountNotFound
+	}
+	return &keystoreSigner{ks: w.ks, account: account}, nil
+}
+
+func (w *keystoreWallet) unlock(account accounts.Account) error {
+	if err := w.ks.Unlock(account, w.passphrase); err != nil {
+		return fmt.Errorf("failed to unlock account %s: %w", account.Address.Hex(), err)
+	}
+	return nil
+}
+
+type keystoreSigner struct {
+	ks      *keystore.KeyStore
+	account accounts.Account
+}
+
+func (s *keystoreSigner) Address() common.Address {
+	return s.account.Address
+}
+
+func (s *keystoreSigner) SignTx(_ context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signedTx, err := s.ks.SignTx(s.account, tx, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign tx: %w", err)
+	}
+	return signedTx, nil
+}
+
+func (s *keystoreSigner) SignHash(_ context.Context, hash common.Hash) ([]byte, error) {
+	sig, err := s.ks.SignHash(s.account, hash.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign hash: %w", err)
+	}
+	return sig, nil
+}
@@ -0,0 +1,72 @@
+package wallet
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"math/big"
+	"testing"
+)
+
+func TestKeystoreWallet(t *testing.T) {
+	const passphrase = "secret"
+	ctx := context.Background()
+	dir := t.TempDir()
+	logger := zap.NewNop()
+
+	w, err := newKeystoreWallet(logger, dir, passphrase, keystore.LightScryptN, keystore.LightScryptP)
+	require.NoError(t, err)
+	addresses, err := w.Accounts()
+	require.NoError(t, err)
+	require.Empty(t, addresses)
+
+	created, err := w.NewAccount()
+	require.NoError(t, err)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	imported, err := w.ImportKey(key)
+	require.NoError(t, err)
+	require.Equal(t, crypto.PubkeyToAddress(key.PublicKey), imported)
+
+	_, err = w.ImportKey(key)
+	require.ErrorIs(t, err, ErrAccountExists)
+
+	addresses, err = w.Accounts()
+	require.NoError(t, err)
+	require.ElementsMatch(t, []common.Address{created, imported}, addresses)
+
+	_, err = w.Signer(common.HexToAddress("0xa512eb36e162bfb0e9f55b56bc2a070ca0d3ecd7"))
+	require.ErrorIs(t, err, ErrAccountNotFound)
+
+	// accounts must be unlocked again after reopening the keystore
+	reopened, err := newKeystoreWallet(logger, dir, passphrase, keystore.LightScryptN, keystore.LightScryptP)
+	require.NoError(t, err)
+
+	signer, err := reopened.Signer(created)
+	require.NoError(t, err)
+	require.Equal(t, created, signer.Address())
+
+	chainID := big.NewInt(314159)
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		GasTipCap: big.NewInt(1e9),
+		GasFeeCap: big.NewInt(3e9),
+		Gas:       21000,
+		To:        &imported,
+		Value:     big.NewInt(1),
+	})
+	signedTx, err := signer.SignTx(ctx, tx, chainID)
+	require.NoError(t, err)
+
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signedTx)
+	require.NoError(t, err)
+	require.Equal(t, created, sender)
+
+	_, err = newKeystoreWallet(logger, dir, "wrong", keystore.LightScryptN, keystore.LightScryptP)
+	require.Error(t, err)
+}
@@ -0,0 +1,180 @@
+package worker
+
+import (
+	"app/internal/blockchain"
+	"app/internal/database"
+	"app/internal/database/models"
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	DefaultBroadcastInterval = 2 * time.Second
+	DefaultBroadcastTimeout  = time.Hour
+)
+
+// Broadcaster sends queued transactions to the network, retrying until the node
+// accepts them, and moves them to pending. Transactions the node keeps rejecting
+// for longer than timeout are moved to failed.
+type Broadcaster struct {
+	logger   *zap.Logger
+	bc       blockchain.Client
+	db       database.Database
+	interval time.Duration
+	timeout  time.Duration
+	now      func() time.Time
+}
+
+func NewBroadcaster(logger *zap.Logger, bc blockchain.Client, db database.Database, interval, timeout time.Duration) *Broadcaster {
+	return &Broadcaster{
+		logger:   logger,
+		bc:       bc,
+		db:       db,
+		interval: interval,
+		timeout:  timeout,
+		now:      time.Now,
+	}
+}
+
+// Run blocks until ctx is cancelled.
+func (b *Broadcaster) Run(ctx context.Context) {
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+
+	for {
+		b.poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			b.logger.Info("Transaction broadcaster stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (b *Broadcaster) poll(ctx context.Context) {
+	txs, err := b.db.GetQueuedTransactions(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			b.logger.Error("failed to get queued transactions", zap.Error(err))
+		}
+		return
+	}
+
+	// a sender's later nonces are held back until the earlier ones are accepted
+	blocked := make(map[string]bool)
+	for _, tx := range txs {
+		if ctx.Err() != nil {
+			return
+		}
+		if blocked[tx.Sender] {
+			continue
+		}
+		if !b.broadcast(ctx, tx) {
+			blocked[tx.Sender] = true
+		}
+	}
+}
+
+// broadcast reports whether tx left the queue.
+func (b *Broadcaster) broadcast(ctx context.Context, tx models.Transaction) bool {
+	// claimed first, so it can't be cancelled while it may already reach the node
+	if err := b.db.ClaimQueuedTransaction(ctx, tx.Hash); err != nil {
+		if errors.Is(err, database.ErrTxNotFound) {
+			b.logger.Info("queued transaction was cancelled", zap.String("hash", tx.Hash))
+			return true
+		}
+		b.logger.Error("failed to claim queued transaction", zap.String("hash", tx.Hash), zap.Error(err))
+		return false
+	}
+
+	signedTx, err := decodeRawTx(tx.RawTx)
+	if err != nil {
+		b.logger.Error("failed to decode queued transaction", zap.String("hash", tx.Hash), zap.Error(err))
+		b.fail(ctx, tx, err)
+		return true
+	}
+
+	err = b.bc.SendTransaction(ctx, signedTx)
+	switch {
+	case err == nil, blockchain.IsKnownTransaction(err):
+	case blockchain.IsNonceConflict(err):
+		// either an earlier attempt went through before the status was updated,
+		// or the nonce was taken by another transaction meanwhile
+		if _, lookupErr := b.bc.GetTransaction(ctx, common.HexToHash(tx.Hash)); lookupErr != nil {
+			if !errors.Is(lookupErr, blockchain.ErrTxNotFound) {
+				b.logger.Error("failed to look up queued transaction", zap.String("hash", tx.Hash), zap.Error(lookupErr))
+				return false
+			}
+			b.logger.Warn("nonce of queued transaction already used", zap.String("hash", tx.Hash), zap.Error(err))
+			b.fail(ctx, tx, err)
+			// nonces were handed out which the chain does not agree with
+			b.bc.ResetNonces(common.HexToAddress(tx.Sender))
+			return true
+		}
+	default:
+		if ctx.Err() != nil {
+			return false
+		}
+		// rejected for too long, e.g. for lack of funds, later nonces of the sender must not wait forever
+		if b.now().Sub(tx.Timestamp) >= b.timeout {
+			b.logger.Warn("failed to broadcast transaction, giving up", zap.String("hash", tx.Hash), zap.Error(err))
+			b.fail(ctx, tx, err)
+			return true
+		}
+		b.logger.Warn("failed to broadcast transaction, retrying", zap.String("hash", tx.Hash), zap.Error(err))
+		if err := b.db.RecordBroadcastFailure(ctx, tx.Hash, err.Error()); err != nil {
+			b.logger.Error("failed to record broadcast failure", zap.String("hash", tx.Hash), zap.Error(err))
+		}
+		// it did not reach the node, so it may be cancelled until the next attempt
+		if err := b.db.UpdateTransactionStatus(ctx, tx.Hash, models.StatusQueued); err != nil {
+			b.logger.Error("failed to update transaction status", zap.String("hash", tx.Hash), zap.Error(err))
+		}
+		return false
+	}
+
+	if err := b.db.UpdateTransactionStatus(ctx, tx.Hash, models.StatusPending); err != nil {
+		b.logger.Error("failed to update transaction status", zap.String("hash", tx.Hash), zap.Error(err))
+		return false
+	}
+
+	b.logger.Info("Transaction broadcast", zap.String("hash", tx.Hash))
+	return true
+}
+
+// fail moves tx to failed and releases its nonce, so the next transaction of the sender fills the gap.
+func (b *Broadcaster) fail(ctx context.Context, tx models.Transaction, reason error) {
+	if err := b.db.RecordBroadcastFailure(ctx, tx.Hash, reason.Error()); err != nil {
+		b.logger.Error("failed to record broadcast failure", zap.String("hash", tx.Hash), zap.Error(err))
+	}
+	if err := b.db.UpdateTransactionStatus(ctx, tx.Hash, models.StatusFailed); err != nil {
+		b.logger.Error("failed to update transaction status", zap.String("hash", tx.Hash), zap.Error(err))
+		return
+	}
+	if tx.Nonce != nil {
+		b.bc.ReleaseNonce(common.HexToAddress(tx.Sender), *tx.Nonce)
+	}
+}
+
+func decodeRawTx(rawTx *string) (*types.Transaction, error) {
+	if rawTx == nil {
+		return nil, errors.New("queued transaction has no raw tx")
+	}
+
+	data, err := hexutil.Decode(*rawTx)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid raw tx")
+	}
+
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(data); err != nil {
+		return nil, errors.Wrap(err, "invalid raw tx")
+	}
+	return tx, nil
+}
@@ -0,0 +1,122 @@
+package worker
+
+import (
+	"app/internal/blockchain"
+	blockchainmock "app/internal/blockchain/mock"
+	"app/internal/database"
+	dbmock "app/internal/database/mock"
+	"app/internal/database/models"
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"math/big"
+	"testing"
+	"time"
+)
+
+var queuedAt = time.Date(2025, 4, 13, 12, 0, 0, 0, time.UTC)
+
+func queuedTransaction(t *testing.T, sender string, nonce uint64) (models.Transaction, *types.Transaction) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	chainID := big.NewInt(314159)
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(chainID), &types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		GasTipCap: big.NewInt(1e9),
+		GasFeeCap: big.NewInt(3e9),
+		Gas:       21000,
+		To:        &common.Address{},
+		Value:     big.NewInt(1000),
+	})
+	require.NoError(t, err)
+
+	rawTx, err := tx.MarshalBinary()
+	require.NoError(t, err)
+	encoded := hexutil.Encode(rawTx)
+	return models.Transaction{Hash: tx.Hash().Hex(), Sender: sender, Nonce: &nonce, Status: models.StatusQueued, RawTx: &encoded, Timestamp: queuedAt}, tx
+}
+
+func TestBroadcaster_Poll(t *testing.T) {
+	const (
+		alice = "0xa512eb36e162bfb0e9f55b56bc2a070ca0d3ecd7"
+		bob   = "0x0c1b7a8a4f8e6d5c4b3a29181716151413121110"
+		carol = "0x7f2b1e7d3c6c2f7b0c1b7a8a4f8e6d5c4b3a2918"
+		dave  = "0x5d5d4d04b70bfe49ad7aac8c4454536070daf180"
+		erin  = "0x3e5e9111ae8eb78fe1cc3bb8915d5d461f3ef9a9"
+	)
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	alice1, alice1Tx := queuedTransaction(t, alice, 1)
+	alice2, _ := queuedTransaction(t, alice, 2)
+	bob1, bob1Tx := queuedTransaction(t, bob, 1)
+	carol1, carol1Tx := queuedTransaction(t, carol, 1)
+	dave1, dave1Tx := queuedTransaction(t, dave, 1)
+	dave1.Timestamp = queuedAt.Add(-time.Hour)
+	dave2, dave2Tx := queuedTransaction(t, dave, 2)
+	erin1, _ := queuedTransaction(t, erin, 1)
+	erin2, erin2Tx := queuedTransaction(t, erin, 2)
+
+	mockDatabase.EXPECT().
+		GetQueuedTransactions(gomock.Any()).
+		Return([]models.Transaction{alice1, alice2, bob1, carol1, dave1, dave2, erin1, erin2}, nil)
+	for _, tx := range []models.Transaction{alice1, bob1, carol1, dave1, dave2, erin2} {
+		mockDatabase.EXPECT().ClaimQueuedTransaction(gomock.Any(), tx.Hash).Return(nil)
+	}
+
+	// alice's first transaction is rejected, so her second one waits
+	sendErr := errors.New("connection refused")
+	mockClient.EXPECT().SendTransaction(gomock.Any(), txWithHash(alice1Tx)).Return(sendErr)
+	mockDatabase.EXPECT().RecordBroadcastFailure(gomock.Any(), alice1.Hash, sendErr.Error()).Return(nil)
+	mockDatabase.EXPECT().UpdateTransactionStatus(gomock.Any(), alice1.Hash, models.StatusQueued).Return(nil)
+
+	// bob's transaction was broadcast before, but its status was not updated
+	mockClient.EXPECT().SendTransaction(gomock.Any(), txWithHash(bob1Tx)).Return(errors.New("already known"))
+	mockDatabase.EXPECT().UpdateTransactionStatus(gomock.Any(), bob1.Hash, models.StatusPending).Return(nil)
+
+	// carol's nonce was taken by another transaction
+	nonceErr := errors.New("nonce too low")
+	mockClient.EXPECT().SendTransaction(gomock.Any(), txWithHash(carol1Tx)).Return(nonceErr)
+	mockClient.EXPECT().GetTransaction(gomock.Any(), carol1Tx.Hash()).Return(nil, blockchain.ErrTxNotFound)
+	mockDatabase.EXPECT().RecordBroadcastFailure(gomock.Any(), carol1.Hash, nonceErr.Error()).Return(nil)
+	mockDatabase.EXPECT().UpdateTransactionStatus(gomock.Any(), carol1.Hash, models.StatusFailed).Return(nil)
+	mockClient.EXPECT().ReleaseNonce(common.HexToAddress(carol), uint64(1))
+	mockClient.EXPECT().ResetNonces(common.HexToAddress(carol))
+
+	// dave's first transaction was rejected for too long, so his second one is sent
+	fundsErr := errors.New("insufficient funds")
+	mockClient.EXPECT().SendTransaction(gomock.Any(), txWithHash(dave1Tx)).Return(fundsErr)
+	mockDatabase.EXPECT().RecordBroadcastFailure(gomock.Any(), dave1.Hash, fundsErr.Error()).Return(nil)
+	mockDatabase.EXPECT().UpdateTransactionStatus(gomock.Any(), dave1.Hash, models.StatusFailed).Return(nil)
+	mockClient.EXPECT().ReleaseNonce(common.HexToAddress(dave), uint64(1))
+	mockClient.EXPECT().SendTransaction(gomock.Any(), txWithHash(dave2Tx)).Return(nil)
+	mockDatabase.EXPECT().UpdateTransactionStatus(gomock.Any(), dave2.Hash, models.StatusPending).Return(nil)
+
+	// erin's first transaction was cancelled meanwhile, so it is not sent
+	mockDatabase.EXPECT().ClaimQueuedTransaction(gomock.Any(), erin1.Hash).Return(database.ErrTxNotFound)
+	mockClient.EXPECT().SendTransaction(gomock.Any(), txWithHash(erin2Tx)).Return(nil)
+	mockDatabase.EXPECT().UpdateTransactionStatus(gomock.Any(), erin2.Hash, models.StatusPending).Return(nil)
+
+	broadcaster := NewBroadcaster(zap.NewNop(), mockClient, mockDatabase, time.Second, time.Hour)
+	broadcaster.now = func() time.Time { return queuedAt.Add(time.Minute) }
+	broadcaster.poll(ctx)
+}
+
+func txWithHash(tx *types.Transaction) gomock.Matcher {
+	return gomock.Cond(func(x any) bool {
+		other, ok := x.(*types.Transaction)
+		return ok && other.Hash() == tx.Hash()
+	})
+}
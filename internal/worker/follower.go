@@ -0,0 +1,139 @@
+package worker
+
+import (
+	"app/internal/blockchain"
+	"app/internal/database"
+	"app/internal/database/models"
+	"app/internal/importer"
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	DefaultFollowerInterval = 30 * time.Second
+	// followerCursor is the name of the block cursor of the follower
+	followerCursor = "incoming"
+	// followerMaxBlocks bounds the blocks scanned by one poll, FIL transfers are
+	// found by fetching every block
+	followerMaxBlocks = 100
+)
+
+// Follower records FIL and iFIL transfers received by watched addresses as incoming
+// transactions. It follows the chain from the block after its cursor, the first run
+// starts at the head. Earlier transfers are backfilled with the importer.
+type Follower struct {
+	logger    *zap.Logger
+	bc        blockchain.Client
+	db        database.Database
+	ifil      *common.Address
+	interval  time.Duration
+	maxBlocks uint64
+}
+
+// NewFollower creates a follower. Without an iFIL token address only FIL transfers are recorded.
+func NewFollower(logger *zap.Logger, bc blockchain.Client, db database.Database, ifil *common.Address, interval time.Duration) *Follower {
+	return &Follower{
+		logger:    logger,
+		bc:        bc,
+		db:        db,
+		ifil:      ifil,
+		interval:  interval,
+		maxBlocks: followerMaxBlocks,
+	}
+}
+
+// Run blocks until ctx is cancelled.
+func (f *Follower) Run(ctx context.Context) {
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := f.poll(ctx); err != nil && ctx.Err() == nil {
+			f.logger.Error("failed to follow incoming transfers", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			f.logger.Info("Incoming transfer follower stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (f *Follower) poll(ctx context.Context) error {
+	head, err := f.bc.BlockNumber(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get chain head")
+	}
+
+	cursor, err := f.db.GetBlockCursor(ctx, followerCursor)
+	if err != nil {
+		if !errors.Is(err, database.ErrNoCursor) {
+			return errors.Wrap(err, "failed to get block cursor")
+		}
+		_, err = f.db.RecordIncomingTransactions(ctx, followerCursor, nil, head)
+		return errors.Wrap(err, "failed to record block cursor")
+	}
+
+	from := cursor.LastBlock + 1
+	if from > head {
+		return nil
+	}
+	to := min(head, from+f.maxBlocks-1)
+
+	txs, err := f.scan(ctx, from, to)
+	if err != nil {
+		return err
+	}
+
+	recorded, err := f.db.RecordIncomingTransactions(ctx, followerCursor, txs, to)
+	if err != nil {
+		return errors.Wrap(err, "failed to record incoming transactions")
+	}
+	if recorded > 0 {
+		f.logger.Info("Incoming transfers recorded", zap.Uint64("from_block", from), zap.Uint64("to_block", to), zap.Int64("recorded", recorded))
+	}
+	return nil
+}
+
+func (f *Follower) scan(ctx context.Context, from, to uint64) ([]models.Transaction, error) {
+	watched, err := f.db.GetWatchedAddresses(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get watched addresses")
+	}
+	// nothing to look for, the cursor still moves so adding an address does not rescan
+	if len(watched) == 0 {
+		return nil, nil
+	}
+
+	filter := blockchain.TransferFilter{To: make([]common.Address, 0, len(watched))}
+	for _, w := range watched {
+		filter.To = append(filter.To, common.HexToAddress(w.Address))
+	}
+
+	transfers, err := f.bc.FindFILTransfers(ctx, filter, from, to)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find FIL transfers")
+	}
+	txs := make([]models.Transaction, 0, len(transfers))
+	for _, transfer := range transfers {
+		txs = append(txs, importer.ExternalTransaction(transfer, models.TokenFIL, models.DirectionIncoming))
+	}
+
+	if f.ifil == nil {
+		return txs, nil
+	}
+
+	transfers, err = f.bc.FindTokenTransfers(ctx, *f.ifil, filter, from, to)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find iFIL transfers")
+	}
+	for _, transfer := range transfers {
+		txs = append(txs, importer.ExternalTransaction(transfer, models.TokenIFIL, models.DirectionIncoming))
+	}
+	return txs, nil
+}
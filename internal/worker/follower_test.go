@@ -0,0 +1,139 @@
+package worker
+
+import (
+	"app/internal/blockchain"
+	blockchainmock "app/internal/blockchain/mock"
+	"app/internal/database"
+	dbmock "app/internal/database/mock"
+	"app/internal/database/models"
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestFollower_Poll(t *testing.T) {
+	const (
+		watched = "0x5d5d4d04b70bfe49ad7aac8c4454536070daf180"
+		sender  = "0xa512eb36e162bfb0e9f55b56bc2a070ca0d3ecd7"
+		ifil    = "0x690908f7fa93afc040cfbd9fe1ddd2c2668aa0e0"
+		txHash  = "0x4033cf2e690e8f6078ab9e665be681c1a9d7c79b6a75d0668da5336fabf43b46"
+	)
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	token := common.HexToAddress(ifil)
+	follower := NewFollower(zap.NewNop(), mockClient, mockDatabase, &token, time.Second)
+	follower.maxBlocks = 10
+
+	filter := blockchain.TransferFilter{To: []common.Address{common.HexToAddress(watched)}}
+	mockClient.EXPECT().BlockNumber(gomock.Any()).Return(uint64(150), nil)
+	mockDatabase.EXPECT().GetBlockCursor(gomock.Any(), followerCursor).Return(&models.BlockCursor{Name: followerCursor, LastBlock: 99}, nil)
+	mockDatabase.EXPECT().GetWatchedAddresses(gomock.Any()).Return([]models.WatchedAddress{{Address: watched}}, nil)
+	mockClient.EXPECT().FindFILTransfers(gomock.Any(), filter, uint64(100), uint64(109)).Return([]blockchain.Transfer{{
+		Hash:        common.HexToHash(txHash),
+		From:        common.HexToAddress(sender),
+		To:          common.HexToAddress(watched),
+		Amount:      big.NewInt(1e18),
+		BlockNumber: 105,
+	}}, nil)
+	mockClient.EXPECT().FindTokenTransfers(gomock.Any(), token, filter, uint64(100), uint64(109)).Return(nil, nil)
+	mockDatabase.EXPECT().
+		RecordIncomingTransactions(gomock.Any(), followerCursor, gomock.Any(), uint64(109)).
+		DoAndReturn(func(_ context.Context, _ string, txs []models.Transaction, _ uint64) (int64, error) {
+			require.Len(t, txs, 1)
+			require.Equal(t, watched, txs[0].Receiver)
+			require.Equal(t, sender, txs[0].Sender)
+			require.Equal(t, models.DirectionIncoming, txs[0].Direction)
+			require.Equal(t, models.SourceExternal, txs[0].Source)
+			require.Equal(t, models.StatusConfirmed, txs[0].Status)
+			return 1, nil
+		})
+
+	require.NoError(t, follower.poll(ctx))
+}
+
+func TestFollower_PollTokenEvents(t *testing.T) {
+	const (
+		watched = "0x5d5d4d04b70bfe49ad7aac8c4454536070daf180"
+		sender  = "0xa512eb36e162bfb0e9f55b56bc2a070ca0d3ecd7"
+		ifil    = "0x690908f7fa93afc040cfbd9fe1ddd2c2668aa0e0"
+		txHash  = "0x15e54e2f6e60be523a4ef44e3dc3ab7245bdc98d8b007bfcf1628a320983384b"
+	)
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	token := common.HexToAddress(ifil)
+	follower := NewFollower(zap.NewNop(), mockClient, mockDatabase, &token, time.Second)
+
+	// one transaction paying the watched address twice
+	firstLog, secondLog := uint(1), uint(4)
+	filter := blockchain.TransferFilter{To: []common.Address{common.HexToAddress(watched)}}
+	mockClient.EXPECT().BlockNumber(gomock.Any()).Return(uint64(100), nil)
+	mockDatabase.EXPECT().GetBlockCursor(gomock.Any(), followerCursor).Return(&models.BlockCursor{Name: followerCursor, LastBlock: 99}, nil)
+	mockDatabase.EXPECT().GetWatchedAddresses(gomock.Any()).Return([]models.WatchedAddress{{Address: watched}}, nil)
+	mockClient.EXPECT().FindFILTransfers(gomock.Any(), filter, uint64(100), uint64(100)).Return(nil, nil)
+	mockClient.EXPECT().FindTokenTransfers(gomock.Any(), token, filter, uint64(100), uint64(100)).Return([]blockchain.Transfer{{
+		Hash:        common.HexToHash(txHash),
+		From:        common.HexToAddress(sender),
+		To:          common.HexToAddress(watched),
+		Amount:      big.NewInt(1000),
+		BlockNumber: 100,
+		LogIndex:    &firstLog,
+	}, {
+		Hash:        common.HexToHash(txHash),
+		From:        common.HexToAddress(sender),
+		To:          common.HexToAddress(watched),
+		Amount:      big.NewInt(3000),
+		BlockNumber: 100,
+		LogIndex:    &secondLog,
+	}}, nil)
+	mockDatabase.EXPECT().
+		RecordIncomingTransactions(gomock.Any(), followerCursor, gomock.Any(), uint64(100)).
+		DoAndReturn(func(_ context.Context, _ string, txs []models.Transaction, _ uint64) (int64, error) {
+			require.Len(t, txs, 2)
+			require.Equal(t, txs[0].Hash, txs[1].Hash)
+			require.Equal(t, firstLog, *txs[0].LogIndex)
+			require.Equal(t, "1000", txs[0].Amount.String())
+			require.Equal(t, secondLog, *txs[1].LogIndex)
+			require.Equal(t, "3000", txs[1].Amount.String())
+			return 2, nil
+		})
+
+	require.NoError(t, follower.poll(ctx))
+}
+
+func TestFollower_PollStart(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	follower := NewFollower(zap.NewNop(), mockClient, mockDatabase, nil, time.Second)
+
+	// the first run starts at the head without scanning
+	mockClient.EXPECT().BlockNumber(gomock.Any()).Return(uint64(150), nil)
+	mockDatabase.EXPECT().GetBlockCursor(gomock.Any(), followerCursor).Return(nil, database.ErrNoCursor)
+	mockDatabase.EXPECT().RecordIncomingTransactions(gomock.Any(), followerCursor, nil, uint64(150)).Return(int64(0), nil)
+	require.NoError(t, follower.poll(ctx))
+
+	// without watched addresses only the cursor moves
+	mockClient.EXPECT().BlockNumber(gomock.Any()).Return(uint64(160), nil)
+	mockDatabase.EXPECT().GetBlockCursor(gomock.Any(), followerCursor).Return(&models.BlockCursor{Name: followerCursor, LastBlock: 150}, nil)
+	mockDatabase.EXPECT().GetWatchedAddresses(gomock.Any()).Return(nil, nil)
+	mockDatabase.EXPECT().RecordIncomingTransactions(gomock.Any(), followerCursor, nil, uint64(160)).Return(int64(0), nil)
+	require.NoError(t, follower.poll(ctx))
+}
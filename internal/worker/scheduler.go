@@ -0,0 +1,121 @@
+package worker
+
+import (
+	"app/internal/cron"
+	"app/internal/database"
+	"app/internal/database/models"
+	"context"
+	"go.uber.org/zap"
+	"time"
+)
+
+const DefaultSchedulerInterval = 30 * time.Second
+
+// Submitter sends the transfer of a schedule and returns its hash.
+type Submitter interface {
+	SubmitScheduled(ctx context.Context, schedule *models.Schedule) (string, error)
+}
+
+// Scheduler submits the transfers of due schedules. Every run is claimed before
+// it is submitted, so a run is never sent twice, even by several instances.
+type Scheduler struct {
+	logger    *zap.Logger
+	db        database.Database
+	submitter Submitter
+	interval  time.Duration
+	now       func() time.Time
+}
+
+func NewScheduler(logger *zap.Logger, db database.Database, submitter Submitter, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		logger:    logger,
+		db:        db,
+		submitter: submitter,
+		interval:  interval,
+		now:       time.Now,
+	}
+}
+
+// Run blocks until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Transfer scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) poll(ctx context.Context) {
+	now := s.now()
+	schedules, err := s.db.GetDueSchedules(ctx, now)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.logger.Error("failed to get due schedules", zap.Error(err))
+		}
+		return
+	}
+
+	for i := range schedules {
+		if ctx.Err() != nil {
+			return
+		}
+		s.run(ctx, &schedules[i], now)
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context, schedule *models.Schedule, now time.Time) {
+	next, err := nextRun(schedule, now)
+	if err != nil {
+		s.logger.Error("failed to compute next run", zap.Uint64("schedule_id", schedule.ID), zap.Error(err))
+	}
+
+	claimed, err := s.db.ClaimScheduleRun(ctx, schedule.ID, *schedule.NextRunAt, next)
+	if err != nil {
+		s.logger.Error("failed to claim schedule run", zap.Uint64("schedule_id", schedule.ID), zap.Error(err))
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	var txHash, runErr *string
+	hash, err := s.submitter.SubmitScheduled(ctx, schedule)
+	if err != nil {
+		s.logger.Error("failed to submit scheduled transfer", zap.Uint64("schedule_id", schedule.ID), zap.Error(err))
+		msg := err.Error()
+		runErr = &msg
+	} else {
+		s.logger.Info("Scheduled transfer submitted", zap.Uint64("schedule_id", schedule.ID), zap.String("hash", hash))
+		txHash = &hash
+	}
+
+	if err := s.db.RecordScheduleRun(ctx, schedule.ID, txHash, runErr); err != nil {
+		s.logger.Error("failed to record schedule run", zap.Uint64("schedule_id", schedule.ID), zap.Error(err))
+	}
+}
+
+// nextRun returns the run after now, or nil for one-off schedules. Runs missed
+// while the service was down are skipped, not caught up.
+func nextRun(schedule *models.Schedule, now time.Time) (*time.Time, error) {
+	if schedule.Cron == nil {
+		return nil, nil
+	}
+
+	expr, err := cron.Parse(*schedule.Cron)
+	if err != nil {
+		return nil, err
+	}
+	next, err := expr.Next(now.UTC())
+	if err != nil {
+		return nil, err
+	}
+	return &next, nil
+}
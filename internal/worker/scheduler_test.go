@@ -0,0 +1,66 @@
+package worker
+
+import (
+	dbmock "app/internal/database/mock"
+	"app/internal/database/models"
+	"context"
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+type submitterFunc func(ctx context.Context, schedule *models.Schedule) (string, error)
+
+func (f submitterFunc) SubmitScheduled(ctx context.Context, schedule *models.Schedule) (string, error) {
+	return f(ctx, schedule)
+}
+
+func TestScheduler_Poll(t *testing.T) {
+	const txHash = "0x4033cf2e690e8f6078ab9e665be681c1a9d7c79b6a75d0668da5336fabf43b46"
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	now := time.Date(2025, time.April, 30, 23, 59, 30, 0, time.UTC)
+	due := now.Add(-time.Minute)
+	monthly := "0 0 1 * *"
+	nextMonth := time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC)
+
+	mockDatabase.EXPECT().
+		GetDueSchedules(gomock.Any(), now).
+		Return([]models.Schedule{
+			{ID: 1, Cron: &monthly, NextRunAt: &due},
+			{ID: 2, NextRunAt: &due},
+			{ID: 3, NextRunAt: &due},
+		}, nil)
+
+	// recurring schedules move to their next run, one-off ones complete
+	mockDatabase.EXPECT().ClaimScheduleRun(gomock.Any(), uint64(1), due, &nextMonth).Return(true, nil)
+	mockDatabase.EXPECT().ClaimScheduleRun(gomock.Any(), uint64(2), due, nil).Return(true, nil)
+	// the run of the third one was claimed by another instance
+	mockDatabase.EXPECT().ClaimScheduleRun(gomock.Any(), uint64(3), due, nil).Return(false, nil)
+
+	hash := txHash
+	mockDatabase.EXPECT().RecordScheduleRun(gomock.Any(), uint64(1), &hash, nil).Return(nil)
+	reason := "insufficient funds"
+	mockDatabase.EXPECT().RecordScheduleRun(gomock.Any(), uint64(2), nil, &reason).Return(nil)
+
+	submitter := submitterFunc(func(_ context.Context, schedule *models.Schedule) (string, error) {
+		switch schedule.ID {
+		case 1:
+			return txHash, nil
+		case 2:
+			return "", errors.New(reason)
+		}
+		t.Fatalf("unexpected submission of schedule %d", schedule.ID)
+		return "", nil
+	})
+
+	scheduler := NewScheduler(zap.NewNop(), mockDatabase, submitter, time.Second)
+	scheduler.now = func() time.Time { return now }
+	scheduler.poll(ctx)
+}
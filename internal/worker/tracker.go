@@ -0,0 +1,278 @@
+package worker
+
+import (
+	"app/internal/blockchain"
+	"app/internal/database"
+	"app/internal/database/models"
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	DefaultTrackerInterval = 15 * time.Second
+	DefaultDropTimeout     = 30 * time.Minute
+	// DefaultConfirmationDepth is the Filecoin finality of 900 epochs
+	DefaultConfirmationDepth = 900
+)
+
+// Tracker polls receipts of pending transactions and moves them to their final status.
+// Transactions which will never be mined are moved to dropped. Confirmed transactions
+// are re-verified until their block is confirmationDepth deep and then moved to finalized.
+type Tracker struct {
+	logger            *zap.Logger
+	bc                blockchain.Client
+	db                database.Database
+	interval          time.Duration
+	dropTimeout       time.Duration
+	confirmationDepth uint64
+	now               func() time.Time
+}
+
+func NewTracker(logger *zap.Logger, bc blockchain.Client, db database.Database, interval, dropTimeout time.Duration, confirmationDepth uint64) *Tracker {
+	return &Tracker{
+		logger:            logger,
+		bc:                bc,
+		db:                db,
+		interval:          interval,
+		dropTimeout:       dropTimeout,
+		confirmationDepth: confirmationDepth,
+		now:               time.Now,
+	}
+}
+
+// Run blocks until ctx is cancelled.
+func (t *Tracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		t.poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			t.logger.Info("Transaction tracker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *Tracker) poll(ctx context.Context) {
+	t.pollPending(ctx)
+	t.pollConfirmed(ctx)
+}
+
+func (t *Tracker) pollPending(ctx context.Context) {
+	txs, err := t.db.GetPendingTransactions(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			t.logger.Error("failed to get pending transactions", zap.Error(err))
+		}
+		return
+	}
+
+	// mined nonces per sender, fetched once per poll
+	nonces := make(map[string]uint64)
+	for _, tx := range txs {
+		if ctx.Err() != nil {
+			return
+		}
+		t.track(ctx, tx, nonces)
+	}
+}
+
+func (t *Tracker) track(ctx context.Context, tx models.Transaction, nonces map[string]uint64) {
+	// the nonce is read before the receipt: if it is used up and there is still no
+	// receipt afterwards, another transaction took it
+	var nonceUsed bool
+	if tx.Nonce != nil {
+		minedNonce, ok := t.minedNonce(ctx, tx.Sender, nonces)
+		nonceUsed = ok && minedNonce > *tx.Nonce
+	}
+
+	receipt, err := t.bc.GetTransactionReceipt(ctx, common.HexToHash(tx.Hash))
+	if err != nil {
+		if !errors.Is(err, blockchain.ErrReceiptNotFound) {
+			if ctx.Err() == nil {
+				t.logger.Error("failed to get transaction receipt", zap.String("hash", tx.Hash), zap.Error(err))
+			}
+			return
+		}
+
+		if nonceUsed {
+			t.drop(ctx, tx.Hash, "nonce used by another transaction")
+			return
+		}
+		t.checkSeen(ctx, tx)
+		return
+	}
+
+	status := receiptStatus(receipt)
+
+	if err := t.db.UpdateTransactionReceipt(ctx, tx.Hash, status, receiptDetails(receipt)); err != nil {
+		t.logger.Error("failed to update transaction status", zap.String("hash", tx.Hash), zap.Error(err))
+		return
+	}
+
+	// the nonce is used up, so the other transactions sharing it will never be mined
+	root := tx.Hash
+	if tx.Replaces != nil {
+		root = *tx.Replaces
+	}
+	superseded := models.StatusReplaced
+	if tx.Kind == models.KindCancel {
+		superseded = models.StatusCancelled
+	}
+	if err := t.db.MarkSuperseded(ctx, root, tx.Hash, superseded); err != nil {
+		t.logger.Error("failed to mark superseded transactions", zap.String("hash", tx.Hash), zap.Error(err))
+	}
+
+	t.logger.Info("Transaction status updated", zap.String("hash", tx.Hash), zap.String("status", string(status)))
+}
+
+func (t *Tracker) pollConfirmed(ctx context.Context) {
+	txs, err := t.db.GetConfirmedTransactions(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			t.logger.Error("failed to get confirmed transactions", zap.Error(err))
+		}
+		return
+	}
+	if len(txs) == 0 {
+		return
+	}
+
+	head, err := t.bc.BlockNumber(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			t.logger.Error("failed to get block number", zap.Error(err))
+		}
+		return
+	}
+
+	for _, tx := range txs {
+		if ctx.Err() != nil {
+			return
+		}
+		t.verify(ctx, tx, head)
+	}
+}
+
+// verify checks that tx is still in the block it was confirmed in and finalizes it
+// once that block is deep enough.
+func (t *Tracker) verify(ctx context.Context, tx models.Transaction, head uint64) {
+	receipt, err := t.bc.GetTransactionReceipt(ctx, common.HexToHash(tx.Hash))
+	if err != nil {
+		if !errors.Is(err, blockchain.ErrReceiptNotFound) {
+			if ctx.Err() == nil {
+				t.logger.Error("failed to get transaction receipt", zap.String("hash", tx.Hash), zap.Error(err))
+			}
+			return
+		}
+
+		// the block is no longer canonical and the transaction was not mined again yet
+		if err := t.db.RevertToPending(ctx, tx.Hash, t.now()); err != nil {
+			t.logger.Error("failed to revert transaction to pending", zap.String("hash", tx.Hash), zap.Error(err))
+			return
+		}
+		t.logger.Warn("Transaction reorganised away, pending again", zap.String("hash", tx.Hash))
+		return
+	}
+
+	details := receiptDetails(receipt)
+	if tx.BlockHash == nil || *tx.BlockHash != details.BlockHash {
+		// mined again in another block, which has to become deep enough itself
+		if err := t.db.UpdateTransactionReceipt(ctx, tx.Hash, receiptStatus(receipt), details); err != nil {
+			t.logger.Error("failed to update transaction status", zap.String("hash", tx.Hash), zap.Error(err))
+			return
+		}
+		t.logger.Warn("Transaction moved to another block", zap.String("hash", tx.Hash), zap.String("block_hash", details.BlockHash))
+		return
+	}
+
+	if head < details.BlockNumber || head-details.BlockNumber+1 < t.confirmationDepth {
+		return
+	}
+	if err := t.db.FinalizeTransaction(ctx, tx.Hash, t.now()); err != nil {
+		t.logger.Error("failed to update transaction status", zap.String("hash", tx.Hash), zap.Error(err))
+		return
+	}
+	t.logger.Info("Transaction finalized", zap.String("hash", tx.Hash))
+}
+
+func (t *Tracker) minedNonce(ctx context.Context, sender string, nonces map[string]uint64) (uint64, bool) {
+	if nonce, ok := nonces[sender]; ok {
+		return nonce, true
+	}
+
+	nonce, err := t.bc.NonceAt(ctx, common.HexToAddress(sender))
+	if err != nil {
+		if ctx.Err() == nil {
+			t.logger.Error("failed to get sender nonce", zap.String("sender", sender), zap.Error(err))
+		}
+		return 0, false
+	}
+	nonces[sender] = nonce
+	return nonce, true
+}
+
+// checkSeen drops tx once neither the mempool nor the chain has known it for the drop timeout.
+func (t *Tracker) checkSeen(ctx context.Context, tx models.Transaction) {
+	now := t.now()
+	_, err := t.bc.GetTransaction(ctx, common.HexToHash(tx.Hash))
+	if err == nil {
+		if err := t.db.MarkSeen(ctx, tx.Hash, now); err != nil {
+			t.logger.Error("failed to update last seen time", zap.String("hash", tx.Hash), zap.Error(err))
+		}
+		return
+	}
+	if !errors.Is(err, blockchain.ErrTxNotFound) {
+		if ctx.Err() == nil {
+			t.logger.Error("failed to look up pending transaction", zap.String("hash", tx.Hash), zap.Error(err))
+		}
+		return
+	}
+
+	lastSeen := tx.Timestamp
+	if tx.LastSeenAt != nil {
+		lastSeen = *tx.LastSeenAt
+	}
+	if now.Sub(lastSeen) >= t.dropTimeout {
+		t.drop(ctx, tx.Hash, "not found in mempool or chain since "+lastSeen.UTC().Format(time.RFC3339))
+	}
+}
+
+func (t *Tracker) drop(ctx context.Context, hash, reason string) {
+	if err := t.db.MarkDropped(ctx, hash, reason); err != nil {
+		t.logger.Error("failed to mark transaction dropped", zap.String("hash", hash), zap.Error(err))
+		return
+	}
+	t.logger.Warn("Transaction dropped", zap.String("hash", hash), zap.String("reason", reason))
+}
+
+func receiptStatus(receipt *types.Receipt) models.TransactionStatus {
+	if receipt.Status == types.ReceiptStatusSuccessful {
+		return models.StatusConfirmed
+	}
+	return models.StatusFailed
+}
+
+func receiptDetails(receipt *types.Receipt) models.Receipt {
+	details := models.Receipt{
+		BlockHash: receipt.BlockHash.Hex(),
+		GasUsed:   receipt.GasUsed,
+	}
+	if receipt.BlockNumber != nil {
+		details.BlockNumber = receipt.BlockNumber.Uint64()
+	}
+	if receipt.EffectiveGasPrice != nil {
+		price := decimal.NewFromBigInt(receipt.EffectiveGasPrice, 0)
+		details.EffectiveGasPrice = &price
+	}
+	return details
+}
@@ -0,0 +1,261 @@
+package worker
+
+import (
+	"app/internal/blockchain"
+	blockchainmock "app/internal/blockchain/mock"
+	dbmock "app/internal/database/mock"
+	"app/internal/database/models"
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestTracker_Poll(t *testing.T) {
+	const (
+		confirmedHash = "0x4033cf2e690e8f6078ab9e665be681c1a9d7c79b6a75d0668da5336fabf43b46"
+		failedHash    = "0x15e54e2f6e60be523a4ef44e3dc3ab7245bdc98d8b007bfcf1628a320983384b"
+		pendingHash   = "0x7f2b1e7d3c6c2f7b0c1b7a8a4f8e6d5c4b3a29181716151413121110f0e0d0c0"
+		blockHash     = "0x1a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f809"
+	)
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	mockDatabase.EXPECT().
+		GetPendingTransactions(gomock.Any()).
+		Return([]models.Transaction{
+			{Hash: confirmedHash, Status: models.StatusPending},
+			{Hash: failedHash, Status: models.StatusPending},
+			{Hash: pendingHash, Status: models.StatusPending},
+		}, nil)
+
+	mockClient.EXPECT().
+		GetTransactionReceipt(gomock.Any(), common.HexToHash(confirmedHash)).
+		Return(&types.Receipt{
+			Status:            types.ReceiptStatusSuccessful,
+			BlockNumber:       big.NewInt(4242),
+			BlockHash:         common.HexToHash(blockHash),
+			GasUsed:           1500000,
+			EffectiveGasPrice: big.NewInt(100200),
+		}, nil)
+	mockClient.EXPECT().
+		GetTransactionReceipt(gomock.Any(), common.HexToHash(failedHash)).
+		Return(&types.Receipt{Status: types.ReceiptStatusFailed}, nil)
+	mockClient.EXPECT().
+		GetTransactionReceipt(gomock.Any(), common.HexToHash(pendingHash)).
+		Return(nil, blockchain.ErrReceiptNotFound)
+	mockClient.EXPECT().
+		GetTransaction(gomock.Any(), common.HexToHash(pendingHash)).
+		Return(&blockchain.TransactionInfo{Pending: true}, nil)
+	mockDatabase.EXPECT().MarkSeen(gomock.Any(), pendingHash, gomock.Any()).Return(nil)
+
+	gasPrice := decimal.NewFromInt(100200)
+	mockDatabase.EXPECT().
+		UpdateTransactionReceipt(gomock.Any(), confirmedHash, models.StatusConfirmed, models.Receipt{
+			BlockNumber:       4242,
+			BlockHash:         blockHash,
+			GasUsed:           1500000,
+			EffectiveGasPrice: &gasPrice,
+		}).
+		Return(nil)
+	mockDatabase.EXPECT().UpdateTransactionReceipt(gomock.Any(), failedHash, models.StatusFailed, gomock.Any()).Return(nil)
+	mockDatabase.EXPECT().MarkSuperseded(gomock.Any(), confirmedHash, confirmedHash, models.StatusReplaced).Return(nil)
+	mockDatabase.EXPECT().MarkSuperseded(gomock.Any(), failedHash, failedHash, models.StatusReplaced).Return(nil)
+
+	mockDatabase.EXPECT().GetConfirmedTransactions(gomock.Any()).Return(nil, nil)
+
+	tracker := NewTracker(zap.NewNop(), mockClient, mockDatabase, time.Second, time.Hour, 10)
+	tracker.poll(ctx)
+}
+
+func TestTracker_PollReplacement(t *testing.T) {
+	const (
+		originalHash    = "0x4033cf2e690e8f6078ab9e665be681c1a9d7c79b6a75d0668da5336fabf43b46"
+		replacementHash = "0x15e54e2f6e60be523a4ef44e3dc3ab7245bdc98d8b007bfcf1628a320983384b"
+	)
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	root := originalHash
+	mockDatabase.EXPECT().
+		GetPendingTransactions(gomock.Any()).
+		Return([]models.Transaction{
+			{Hash: originalHash, Status: models.StatusPending},
+			{Hash: replacementHash, Status: models.StatusPending, Replaces: &root},
+		}, nil)
+
+	mockClient.EXPECT().
+		GetTransactionReceipt(gomock.Any(), common.HexToHash(originalHash)).
+		Return(nil, blockchain.ErrReceiptNotFound)
+	mockClient.EXPECT().
+		GetTransaction(gomock.Any(), common.HexToHash(originalHash)).
+		Return(&blockchain.TransactionInfo{Pending: true}, nil)
+	mockDatabase.EXPECT().MarkSeen(gomock.Any(), originalHash, gomock.Any()).Return(nil)
+	mockClient.EXPECT().
+		GetTransactionReceipt(gomock.Any(), common.HexToHash(replacementHash)).
+		Return(&types.Receipt{Status: types.ReceiptStatusSuccessful}, nil)
+
+	mockDatabase.EXPECT().UpdateTransactionReceipt(gomock.Any(), replacementHash, models.StatusConfirmed, gomock.Any()).Return(nil)
+	mockDatabase.EXPECT().MarkSuperseded(gomock.Any(), originalHash, replacementHash, models.StatusReplaced).Return(nil)
+
+	mockDatabase.EXPECT().GetConfirmedTransactions(gomock.Any()).Return(nil, nil)
+
+	tracker := NewTracker(zap.NewNop(), mockClient, mockDatabase, time.Second, time.Hour, 10)
+	tracker.poll(ctx)
+}
+
+func TestTracker_PollCancellation(t *testing.T) {
+	const (
+		originalHash = "0x4033cf2e690e8f6078ab9e665be681c1a9d7c79b6a75d0668da5336fabf43b46"
+		cancelHash   = "0x15e54e2f6e60be523a4ef44e3dc3ab7245bdc98d8b007bfcf1628a320983384b"
+	)
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	root := originalHash
+	mockDatabase.EXPECT().
+		GetPendingTransactions(gomock.Any()).
+		Return([]models.Transaction{
+			{Hash: cancelHash, Status: models.StatusPending, Replaces: &root, Kind: models.KindCancel},
+		}, nil)
+
+	mockClient.EXPECT().
+		GetTransactionReceipt(gomock.Any(), common.HexToHash(cancelHash)).
+		Return(&types.Receipt{Status: types.ReceiptStatusSuccessful}, nil)
+
+	mockDatabase.EXPECT().UpdateTransactionReceipt(gomock.Any(), cancelHash, models.StatusConfirmed, gomock.Any()).Return(nil)
+	mockDatabase.EXPECT().MarkSuperseded(gomock.Any(), originalHash, cancelHash, models.StatusCancelled).Return(nil)
+
+	mockDatabase.EXPECT().GetConfirmedTransactions(gomock.Any()).Return(nil, nil)
+
+	tracker := NewTracker(zap.NewNop(), mockClient, mockDatabase, time.Second, time.Hour, 10)
+	tracker.poll(ctx)
+}
+
+func TestTracker_PollDropped(t *testing.T) {
+	const (
+		sender      = "0x5d5d4d04b70bfe49ad7aac8c4454536070daf180"
+		takenHash   = "0x4033cf2e690e8f6078ab9e665be681c1a9d7c79b6a75d0668da5336fabf43b46"
+		evictedHash = "0x15e54e2f6e60be523a4ef44e3dc3ab7245bdc98d8b007bfcf1628a320983384b"
+		recentHash  = "0x7f2b1e7d3c6c2f7b0c1b7a8a4f8e6d5c4b3a29181716151413121110f0e0d0c0"
+	)
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	now := time.Date(2025, 4, 13, 12, 0, 0, 0, time.UTC)
+	lastSeen := now.Add(-2 * time.Hour)
+	takenNonce, evictedNonce, recentNonce := uint64(6), uint64(7), uint64(8)
+	mockDatabase.EXPECT().
+		GetPendingTransactions(gomock.Any()).
+		Return([]models.Transaction{
+			{Hash: takenHash, Sender: sender, Status: models.StatusPending, Nonce: &takenNonce, Timestamp: now},
+			{Hash: evictedHash, Sender: sender, Status: models.StatusPending, Nonce: &evictedNonce, Timestamp: lastSeen, LastSeenAt: &lastSeen},
+			{Hash: recentHash, Sender: sender, Status: models.StatusPending, Nonce: &recentNonce, Timestamp: now.Add(-time.Minute)},
+		}, nil)
+
+	// fetched once for all transactions of the sender
+	mockClient.EXPECT().NonceAt(gomock.Any(), common.HexToAddress(sender)).Return(uint64(7), nil)
+	for _, hash := range []string{takenHash, evictedHash, recentHash} {
+		mockClient.EXPECT().
+			GetTransactionReceipt(gomock.Any(), common.HexToHash(hash)).
+			Return(nil, blockchain.ErrReceiptNotFound)
+	}
+	mockClient.EXPECT().GetTransaction(gomock.Any(), common.HexToHash(evictedHash)).Return(nil, blockchain.ErrTxNotFound)
+	mockClient.EXPECT().GetTransaction(gomock.Any(), common.HexToHash(recentHash)).Return(nil, blockchain.ErrTxNotFound)
+
+	mockDatabase.EXPECT().MarkDropped(gomock.Any(), takenHash, "nonce used by another transaction").Return(nil)
+	mockDatabase.EXPECT().MarkDropped(gomock.Any(), evictedHash, "not found in mempool or chain since 2025-04-13T10:00:00Z").Return(nil)
+
+	mockDatabase.EXPECT().GetConfirmedTransactions(gomock.Any()).Return(nil, nil)
+
+	tracker := NewTracker(zap.NewNop(), mockClient, mockDatabase, time.Second, time.Hour, 10)
+	tracker.now = func() time.Time { return now }
+	tracker.poll(ctx)
+}
+
+func TestTracker_PollConfirmed(t *testing.T) {
+	const (
+		finalHash    = "0x4033cf2e690e8f6078ab9e665be681c1a9d7c79b6a75d0668da5336fabf43b46"
+		shallowHash  = "0x15e54e2f6e60be523a4ef44e3dc3ab7245bdc98d8b007bfcf1628a320983384b"
+		reorgedHash  = "0x7f2b1e7d3c6c2f7b0c1b7a8a4f8e6d5c4b3a29181716151413121110f0e0d0c0"
+		movedHash    = "0x2c1e3b5a79d8f6e4c2a0b9d7f5e3c1a08f6d4b2a09e7c5a3f1d9b7e5c3a1f0d2"
+		revertedHash = "0x6a0c2e4f81b3d5f7092b4d6f8a1c3e5f70a2c4e6f8b1d3f5a7c9e1b3d5f7a9c1"
+		unminedHash  = "0x3e5a7c9e0b2d4f6a8c1e3a5c7e9b0d2f2c8e4f0a6b1d3e5f7a9c0b2d4e6f8a1c"
+		blockHash    = "0x1a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f809"
+		newBlockHash = "0x9f8e7d6c5b4a39281706f5e4d3c2b1a09f8e7d6c5b4a39281706f5e4d3c2b1a0"
+	)
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := blockchainmock.NewMockClient(ctrl)
+	mockDatabase := dbmock.NewMockDatabase(ctrl)
+
+	stored := blockHash
+	finalBlock, shallowBlock := uint64(100), uint64(105)
+	mockDatabase.EXPECT().GetPendingTransactions(gomock.Any()).Return(nil, nil)
+	mockDatabase.EXPECT().
+		GetConfirmedTransactions(gomock.Any()).
+		Return([]models.Transaction{
+			{Hash: finalHash, Status: models.StatusConfirmed, BlockNumber: &finalBlock, BlockHash: &stored},
+			{Hash: shallowHash, Status: models.StatusConfirmed, BlockNumber: &shallowBlock, BlockHash: &stored},
+			{Hash: reorgedHash, Status: models.StatusConfirmed, BlockNumber: &shallowBlock, BlockHash: &stored},
+			{Hash: movedHash, Status: models.StatusConfirmed, BlockNumber: &shallowBlock, BlockHash: &stored},
+			// reverted transactions are final or reorganised away like successful ones
+			{Hash: revertedHash, Status: models.StatusFailed, BlockNumber: &finalBlock, BlockHash: &stored},
+			{Hash: unminedHash, Status: models.StatusFailed, BlockNumber: &shallowBlock, BlockHash: &stored},
+		}, nil)
+	mockClient.EXPECT().BlockNumber(gomock.Any()).Return(uint64(110), nil)
+
+	mockClient.EXPECT().
+		GetTransactionReceipt(gomock.Any(), common.HexToHash(finalHash)).
+		Return(&types.Receipt{Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(100), BlockHash: common.HexToHash(blockHash)}, nil)
+	mockClient.EXPECT().
+		GetTransactionReceipt(gomock.Any(), common.HexToHash(shallowHash)).
+		Return(&types.Receipt{Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(105), BlockHash: common.HexToHash(blockHash)}, nil)
+	mockClient.EXPECT().
+		GetTransactionReceipt(gomock.Any(), common.HexToHash(reorgedHash)).
+		Return(nil, blockchain.ErrReceiptNotFound)
+	mockClient.EXPECT().
+		GetTransactionReceipt(gomock.Any(), common.HexToHash(movedHash)).
+		Return(&types.Receipt{Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(107), BlockHash: common.HexToHash(newBlockHash)}, nil)
+
+	mockClient.EXPECT().
+		GetTransactionReceipt(gomock.Any(), common.HexToHash(revertedHash)).
+		Return(&types.Receipt{Status: types.ReceiptStatusFailed, BlockNumber: big.NewInt(100), BlockHash: common.HexToHash(blockHash)}, nil)
+	mockClient.EXPECT().
+		GetTransactionReceipt(gomock.Any(), common.HexToHash(unminedHash)).
+		Return(nil, blockchain.ErrReceiptNotFound)
+
+	mockDatabase.EXPECT().FinalizeTransaction(gomock.Any(), finalHash, gomock.Any()).Return(nil)
+	mockDatabase.EXPECT().FinalizeTransaction(gomock.Any(), revertedHash, gomock.Any()).Return(nil)
+	mockDatabase.EXPECT().RevertToPending(gomock.Any(), reorgedHash, gomock.Any()).Return(nil)
+	mockDatabase.EXPECT().RevertToPending(gomock.Any(), unminedHash, gomock.Any()).Return(nil)
+	mockDatabase.EXPECT().
+		UpdateTransactionReceipt(gomock.Any(), movedHash, models.StatusConfirmed, models.Receipt{BlockNumber: 107, BlockHash: newBlockHash}).
+		Return(nil)
+
+	tracker := NewTracker(zap.NewNop(), mockClient, mockDatabase, time.Second, time.Hour, 10)
+	tracker.poll(ctx)
+}
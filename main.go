@@ -3,10 +3,18 @@ package main
 import (
 	"app/internal/blockchain"
 	"app/internal/database"
+	"app/internal/importer"
 	"app/internal/server"
+	"app/internal/wallet"
+	"app/internal/worker"
 	"context"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"go.uber.org/zap"
 )
 
@@ -30,22 +38,109 @@ func main() {
 		logger.Fatal("Missing SERVER_LISTEN_ADDR")
 	}
 
-	client, err := blockchain.NewClient(logger, chainId, blockchain.DefaultTestnetRPCConfig)
+	dbDriver, err := database.NewDriver(logger, dbDSN)
 	if err != nil {
-		logger.Fatal("Failed to initialize blockchain client", zap.Error(err))
+		logger.Fatal("Failed to connect to database", zap.Error(err))
 	}
 
-	dbDriver, err := database.NewDriver(logger, dbDSN)
+	// durationFromEnv returns the positive duration set in the environment variable
+	// name, or def if it is not set
+	durationFromEnv := func(name string, def time.Duration) time.Duration {
+		v := os.Getenv(name)
+		if v == "" {
+			return def
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			logger.Fatal("Invalid "+name, zap.String("value", v), zap.Error(err))
+		}
+		return d
+	}
+
+	// queued transactions hold nonces the node does not know about
+	client, err := blockchain.NewClient(logger, chainId, blockchain.DefaultTestnetRPCConfig, dbDriver)
 	if err != nil {
-		logger.Fatal("Failed to connect to database", zap.Error(err))
+		logger.Fatal("Failed to initialize blockchain client", zap.Error(err))
+	}
+
+	trackerInterval := durationFromEnv("TX_TRACKER_INTERVAL", worker.DefaultTrackerInterval)
+	dropTimeout := durationFromEnv("TX_DROP_TIMEOUT", worker.DefaultDropTimeout)
+
+	confirmationDepth := uint64(worker.DefaultConfirmationDepth)
+	if v := os.Getenv("CONFIRMATION_DEPTH"); v != "" {
+		confirmationDepth, err = strconv.ParseUint(v, 10, 64)
+		if err != nil || confirmationDepth == 0 {
+			logger.Fatal("Invalid CONFIRMATION_DEPTH", zap.String("value", v), zap.Error(err))
+		}
+	}
+
+	schedulerInterval := durationFromEnv("SCHEDULER_INTERVAL", worker.DefaultSchedulerInterval)
+	broadcastInterval := durationFromEnv("TX_BROADCAST_INTERVAL", worker.DefaultBroadcastInterval)
+	broadcastTimeout := durationFromEnv("TX_BROADCAST_TIMEOUT", worker.DefaultBroadcastTimeout)
+	followerInterval := durationFromEnv("FOLLOWER_INTERVAL", worker.DefaultFollowerInterval)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	keystoreDir := os.Getenv("KEYSTORE_DIR")
+	signerEndpoint := os.Getenv("SIGNER_ENDPOINT")
+	if keystoreDir != "" && signerEndpoint != "" {
+		logger.Fatal("KEYSTORE_DIR and SIGNER_ENDPOINT are mutually exclusive")
+	}
+
+	var w wallet.Wallet
+	switch {
+	case signerEndpoint != "":
+		signerTimeout := durationFromEnv("SIGNER_TIMEOUT", wallet.DefaultRemoteTimeout)
+		w, err = wallet.NewRemote(logger, signerEndpoint, signerTimeout)
+		if err != nil {
+			logger.Fatal("Failed to connect to remote signer", zap.Error(err))
+		}
+	case keystoreDir != "":
+		passphrase := os.Getenv("KEYSTORE_PASSPHRASE")
+		if passphrase == "" {
+			logger.Fatal("Missing KEYSTORE_PASSPHRASE")
+		}
+
+		w, err = wallet.NewKeystore(logger, keystoreDir, passphrase)
+		if err != nil {
+			logger.Fatal("Failed to open keystore", zap.Error(err))
+		}
+	}
+
+	var ifil *common.Address
+	if v := os.Getenv("IFIL_ADDRESS"); v != "" {
+		if !common.IsHexAddress(v) {
+			logger.Fatal("Invalid IFIL_ADDRESS", zap.String("value", v))
+		}
+		token := common.HexToAddress(v)
+		ifil = &token
 	}
+	imp := importer.New(logger, client, dbDriver, ifil)
 
-	srv := server.NewServer(client, dbDriver, logger)
+	srv := server.NewServer(client, dbDriver, w, imp, logger)
 
 	logger.Info("Starting server", zap.String("address", serverListenAddr))
 	srv.Start(serverListenAddr)
 
-	ctx := context.Background()
+	tracker := worker.NewTracker(logger, client, dbDriver, trackerInterval, dropTimeout, confirmationDepth)
+	go tracker.Run(ctx)
+
+	broadcaster := worker.NewBroadcaster(logger, client, dbDriver, broadcastInterval, broadcastTimeout)
+	go broadcaster.Run(ctx)
+
+	scheduler := worker.NewScheduler(logger, dbDriver, srv, schedulerInterval)
+	go scheduler.Run(ctx)
+
+	follower := worker.NewFollower(logger, client, dbDriver, ifil, followerInterval)
+	go follower.Run(ctx)
+
 	<-ctx.Done()
-	srv.Stop(ctx)
+	logger.Info("Shutting down")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Stop(shutdownCtx); err != nil {
+		logger.Error("Failed to stop server", zap.Error(err))
+	}
 }